@@ -32,6 +32,7 @@ import (
 	"go.ketch.com/lib/orlop/v2/service"
 	"go.uber.org/fx"
 	stdlog "log"
+	"os"
 	"reflect"
 	"sort"
 	"strings"
@@ -258,7 +259,15 @@ func Run(prefix string, runner any, cfg any) {
 
 	NewRunner(prefix).SetupRoot(cmd).Setup(cmd, runner, cfg)
 
+	// Flag errors are usage errors, which exit with 64 (EX_USAGE)
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return errors.Invalid(err)
+	})
+
+	// The exit code is derived as by `cmd.Runner.ExitCode`, which cannot be used here as package cmd depends on this
+	// one
 	if err := cmd.Execute(); err != nil {
-		log.WithError(err).Fatal(err)
+		log.WithError(err).Log(logrus.FatalLevel, err)
+		os.Exit(errors.ExitCode(err))
 	}
 }
@@ -24,11 +24,13 @@ import (
 	"context"
 	"fmt"
 	stdlog "log"
+	"os"
 	"sort"
 	"strings"
 
 	"go.ketch.com/lib/orlop/v2"
 	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -43,12 +45,24 @@ import (
 type Runner struct {
 	prefix      string
 	prevPreRunE func(cmd *cobra.Command, args []string) error
+	exitCodes   map[string]int
+	exit        func(code int)
+	forceExit   func(code int)
+	build       service.BuildInfo
+	commands    []Command
 }
 
+// osExit ends the process when a second shutdown signal forces the exit. Unlike the function given to WithExit, which
+// only reports the exit code of a normal shutdown (e.g., by Execute), it must not return.
+var osExit = os.Exit
+
 // NewRunner creates a new Runner
 func NewRunner(prefix string) *Runner {
 	return &Runner{
-		prefix: prefix,
+		prefix:    prefix,
+		exit:      os.Exit,
+		forceExit: osExit,
+		build:     service.ReadBuildInfo(),
 	}
 }
 
@@ -64,6 +78,13 @@ func (r *Runner) SetupRoot(cmd *cobra.Command) *Runner {
 		cmd.PersistentFlags().StringSlice("config", nil, "specifies a .env configuration file to load")
 	}
 
+	r.registerCompletions(cmd)
+
+	// Flag errors are usage errors, which exit with 64 (EX_USAGE)
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return errors.Invalid(err)
+	})
+
 	r.prevPreRunE = cmd.PersistentPreRunE
 
 	if r.prevPreRunE == nil {
@@ -82,7 +103,27 @@ func (r *Runner) Setup(cmd *cobra.Command, options ...fx.Option) *Runner {
 		cmd.RunE = r.runE(options...)
 	}
 
-	cmd.AddCommand(&cobra.Command{
+	cmd.AddCommand(r.initCommand(options...))
+	cmd.AddCommand(r.envCommand(options...))
+	cmd.AddCommand(r.checkCommand(options...))
+	cmd.AddCommand(r.graphCommand(options...))
+	cmd.AddCommand(r.jobsCommand(options...))
+	cmd.AddCommand(r.versionCommand())
+	cmd.AddCommand(r.docsCommand(r.configVars(options...)))
+
+	r.addCommands(cmd, options...)
+
+	// Add the completion command now rather than when executed, so it is included in the docs
+	if !cmd.HasParent() {
+		cmd.InitDefaultCompletionCmd()
+	}
+
+	return r
+}
+
+// initCommand returns the `init` command, which outputs the config environment variables
+func (r *Runner) initCommand(options ...fx.Option) *cobra.Command {
+	return &cobra.Command{
 		Use:   "init",
 		Short: "output the config environment variables and exits",
 		Args:  cobra.NoArgs,
@@ -90,40 +131,75 @@ func (r *Runner) Setup(cmd *cobra.Command, options ...fx.Option) *Runner {
 			var cfgMgr config.Provider
 			app := fx.New(
 				fx.NopLogger,
-				fx.Provide(func() context.Context { return cmd.Context() }),
-				fx.Supply(cmd),
-				fx.Supply(service.Name(r.prefix)),
-				fx.Supply(logging.FatalLevel),
-				orlop.Module,
-				fx.Options(options...),
+				r.appOptions(cmd, logging.FatalLevel, options...),
 				fx.Populate(&cfgMgr),
 			)
 
 			if err := app.Start(context.Background()); err != nil {
-				panic(err)
+				return err
 			}
 			defer app.Stop(context.Background())
 
 			vars, err := cfgMgr.List(cmd.Context())
 			if err != nil {
-				log.WithError(err).Fatal("could not create variables")
+				return errors.Wrap(err, "could not create variables")
 			}
 
 			sort.Strings(vars)
 
 			for _, v := range vars {
 				if strings.Contains(v, "=#") {
-					fmt.Println("#" + v)
+					fmt.Fprintln(cmd.OutOrStdout(), "#"+v)
 				} else {
-					fmt.Println(v)
+					fmt.Fprintln(cmd.OutOrStdout(), v)
 				}
 			}
 
 			return nil
 		},
-	})
+	}
+}
 
-	return r
+// appOptions returns the options shared by every app the Runner builds for the command
+func (r *Runner) appOptions(cmd *cobra.Command, level logging.Level, options ...fx.Option) fx.Option {
+	return fx.Options(
+		fx.Provide(func() context.Context { return cmd.Context() }),
+		fx.Supply(cmd),
+		fx.Supply(service.Name(r.prefix)),
+		fx.Supply(r.build, r.build.Version),
+		fx.Supply(level),
+		orlop.Module,
+		Module,
+		fx.Options(options...),
+	)
+}
+
+// errInspected stops the app being built by inspect once the targets are populated
+var errInspected = errors.New("inspected")
+
+// inspect builds the app graph for the given options without starting it. The targets are populated before any
+// other invoke runs, and the remaining invokes are skipped, so inspection never depends on what they would do (e.g.,
+// connect to external services).
+func (r *Runner) inspect(cmd *cobra.Command, options []fx.Option, targets ...any) error {
+	var populated bool
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Module("inspect",
+			fx.Populate(targets...),
+			fx.Invoke(func() error {
+				populated = true
+				return errInspected
+			}),
+		),
+		r.appOptions(cmd, logging.FatalLevel, options...),
+	)
+
+	if !populated {
+		return app.Err()
+	}
+
+	return nil
 }
 
 func (r *Runner) preRunE(cmd *cobra.Command, args []string) error {
@@ -163,19 +239,31 @@ func (r *Runner) runE(options ...fx.Option) func(cmd *cobra.Command, args []stri
 			return err
 		}
 
+		cfg, err := r.lifecycleConfig(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var p runParams
 		app := fx.New(
 			logging.WithLogger(l),
-			fx.Provide(func() context.Context { return cmd.Context() }),
-			fx.Supply(cmd),
-			fx.Supply(service.Name(r.prefix)),
-			fx.Supply(logging.Level(loglevelFlag)),
-			orlop.Module,
-			fx.Options(options...),
+			fx.StartTimeout(cfg.StartTimeout),
+			fx.StopTimeout(cfg.StopTimeout),
+			r.appOptions(cmd, logging.Level(loglevelFlag), options...),
+			fx.Invoke(func(params runParams) { p = params }),
 		)
 
-		app.Run()
+		// Like fx.App.Run, exit the process if the app fails to start or stop cleanly
+		code, err := r.run(cmd, app, cfg, p)
+		if err != nil {
+			r.exit(r.ExitCode(err))
+			return err
+		} else if code != 0 {
+			r.exit(code)
+			return exitError(code)
+		}
 
-		return app.Err()
+		return nil
 	}
 }
 
@@ -221,18 +309,3 @@ func (r *Runner) SetupLogging(e env.Environment, loglevel string) {
 
 	stdlog.SetOutput(logrus.New().Writer())
 }
-
-// Run loads config and then executes the given runner
-func Run(prefix string, module fx.Option) {
-	var cmd = &cobra.Command{
-		Use:              prefix,
-		TraverseChildren: true,
-		SilenceUsage:     true,
-	}
-
-	NewRunner(prefix).SetupRoot(cmd).Setup(cmd, module)
-
-	if err := cmd.Execute(); err != nil {
-		log.WithError(err).Fatal(err)
-	}
-}
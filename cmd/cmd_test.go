@@ -1,18 +1,30 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.ketch.com/lib/orlop/v2/config"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/scheduler"
+	"go.ketch.com/lib/orlop/v2/service"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
 )
 
 type EmbeddedConfig struct {
@@ -150,6 +162,48 @@ func TestInit(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestEnvEdit(t *testing.T) {
+	dir := t.TempDir()
+	key, err := env.NewKey()
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(key.String()), 0o600))
+
+	file := filepath.Join(dir, ".env.test.enc")
+	require.NoError(t, os.WriteFile(file, must(key.Encrypt([]byte("A=1\n"))), 0o644))
+
+	t.Setenv("VISUAL", "true")
+	prev := memoryTempDir
+	memoryTempDir = filepath.Join(dir, "missing")
+	t.Cleanup(func() {
+		memoryTempDir = prev
+	})
+
+	edit := func(args ...string) error {
+		cmd := &cobra.Command{Use: "test"}
+		NewRunner("test").SetupRoot(cmd).Setup(cmd)
+		cmd.SetArgs(append([]string{"env", "edit", "--key-file", keyFile, file}, args...))
+		return cmd.Execute()
+	}
+
+	// Without a memory-backed directory, the plaintext is only written to disk when explicitly allowed
+	err = edit()
+	require.Error(t, err)
+	assert.Equal(t, errors.ECONFIGURATION, errors.Code(err))
+
+	require.NoError(t, edit("--unsafe-tmpdir", dir))
+	plaintext, err := key.Decrypt(must(os.ReadFile(file)))
+	require.NoError(t, err)
+	assert.Equal(t, "A=1\n", string(plaintext))
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 func TestOptions(t *testing.T) {
 	module := fx.Options(
 		fx.Invoke(
@@ -201,3 +255,643 @@ func testOptions(module fx.Option, options ...fx.Option) error {
 
 	return cmd.Execute()
 }
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("LINT_CONFIG_REQ=here\nLINT_CONFIG_UNUSED=1\nLINT_LOGLEVEL=info\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.test"), []byte("LINT_CONFIG_PTR=abc\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.production"), []byte("LINT_CONFIG_REQ=\n"), 0o600))
+
+	var cmd = &cobra.Command{
+		Use:              "lint",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	NewRunner("lint").SetupRoot(cmd).Setup(cmd, config.Option[TestConfig]("config"))
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"env", "lint", "--dir", dir, "--format", "json"})
+	require.Error(t, cmd.Execute())
+
+	var issues []LintIssue
+	require.NoError(t, json.Unmarshal(out.Bytes(), &issues))
+	assert.ElementsMatch(t, []LintIssue{
+		{Issue: config.Issue{Key: "LINT_CONFIG_UNUSED", Kind: config.UnknownKey, Message: "not used by any config"}, File: ".env"},
+		{Issue: config.Issue{Key: "LINT_CONFIG_PTR", Kind: config.InvalidValue, Message: "could not parse 'abc' as integer"}, File: ".env.test"},
+		{Issue: config.Issue{Key: "LINT_CONFIG_REQ", Kind: config.MissingKey, Message: "required"}, Environment: "production"},
+	}, issues)
+}
+
+func TestCheckAndGraph(t *testing.T) {
+	type missing struct{}
+	type dependent struct{}
+
+	newCmd := func(options ...fx.Option) *cobra.Command {
+		cmd := &cobra.Command{
+			Use:              "graph",
+			TraverseChildren: true,
+			SilenceUsage:     true,
+			SilenceErrors:    true,
+		}
+		NewRunner("graph").SetupRoot(cmd).Setup(cmd, options...)
+		return cmd
+	}
+
+	var out bytes.Buffer
+	cmd := newCmd(fx.Provide(func(*missing) *dependent { return nil }), fx.Invoke(func(*dependent) {}))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"check"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, 78, errors.ExitCode(err))
+	assert.Contains(t, out.String(), "hint: nothing provides *cmd.missing")
+
+	out.Reset()
+	cmd = newCmd(config.Option[TestConfig]("config"), fx.Invoke(func() { t.Fatal("invoke should not run") }))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"check"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "ok\n", out.String())
+
+	jsonGraph := func(cmd *cobra.Command) graph {
+		out.Reset()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"graph", "--format", "json"})
+		require.NoError(t, cmd.Execute())
+
+		var g graph
+		require.NoError(t, json.Unmarshal(out.Bytes(), &g))
+		return g
+	}
+
+	// The configs registered by the app are compared to those of the same app without the test config, so the test
+	// does not depend on the configs registered by orlop itself
+	base := jsonGraph(newCmd())
+	g := jsonGraph(cmd)
+
+	var found bool
+	for _, c := range g.Constructors {
+		if len(c.Results) == 1 && c.Results[0] == "config.Provider" {
+			found = true
+			assert.Contains(t, c.Params, graphParam{Type: "config.Definition[group=configs]"})
+		}
+	}
+	assert.True(t, found)
+	require.Len(t, g.Groups, len(base.Groups))
+	require.NotEmpty(t, g.Groups)
+	assert.Equal(t, "configs", g.Groups[0].Name)
+	assert.Equal(t, "config.Definition", g.Groups[0].Type)
+	assert.Len(t, g.Groups[0].Results, len(base.Groups[0].Results)+1)
+}
+
+func TestParseDotGraph(t *testing.T) {
+	g, err := parseDotGraph("")
+	require.NoError(t, err)
+	assert.Empty(t, g.Constructors)
+
+	_, err = parseDotGraph("digraph {\n\tnode [shape=box];\n}")
+	require.Error(t, err)
+	assert.Equal(t, errors.EINTERNAL, errors.Code(err))
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name   string
+		module fx.Option
+		want   int
+	}{
+		{
+			name: "configuration",
+			module: fx.Invoke(func() error {
+				return errors.Configurationf("missing configuration")
+			}),
+			want: 78,
+		},
+		{
+			name: "unavailable",
+			module: fx.Invoke(func(lifecycle fx.Lifecycle) {
+				lifecycle.Append(fx.StartHook(func() error {
+					return errors.Unavailablef("upstream unavailable")
+				}))
+			}),
+			want: 69,
+		},
+		{
+			name: "exit code",
+			module: fx.Invoke(func(lifecycle fx.Lifecycle, s fx.Shutdowner) {
+				lifecycle.Append(fx.StartHook(func() error {
+					return s.Shutdown(fx.ExitCode(3))
+				}))
+			}),
+			want: 3,
+		},
+		{
+			name: "success",
+			module: fx.Invoke(func(lifecycle fx.Lifecycle, s fx.Shutdowner) {
+				lifecycle.Append(fx.StartHook(func() error {
+					return s.Shutdown()
+				}))
+			}),
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Execute("test", tt.module))
+		})
+	}
+}
+
+func TestExecuteUsage(t *testing.T) {
+	setup := func(r *Runner, cmd *cobra.Command) {
+		r.Setup(cmd, fx.Options())
+	}
+
+	assert.Equal(t, 64, execute("test", []string{"--bogus"}, setup))
+	assert.Equal(t, 64, execute("test", []string{"init", "extra"}, setup))
+	assert.Equal(t, 64, execute("test", []string{"env", "encrypt"}, setup))
+}
+
+func TestTask(t *testing.T) {
+	os.Setenv("TEST_STRING", "string-data")
+
+	var data string
+	assert.Equal(t, 0, Execute("test", fx.Options(
+		config.Option[TestString](),
+		Task(func(ctx context.Context, s TestString) error {
+			data = s.String
+			return nil
+		}),
+	)))
+	assert.Equal(t, "string-data", data)
+
+	assert.Equal(t, 64, Execute("test", Task(func(ctx context.Context) error {
+		return errors.Invalidf("invalid input")
+	})))
+
+	// An invalid task is a programming error
+	assert.Equal(t, 70, Execute("test", Task(func() {})))
+	assert.Equal(t, 70, Execute("test", Task(nil)))
+	assert.Equal(t, 70, Execute("test", Task((func(context.Context) error)(nil))))
+}
+
+func TestJobsRun(t *testing.T) {
+	var runs int
+	var code int
+
+	cmd := &cobra.Command{
+		Use:              "jobs",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	r := NewRunner("jobs")
+	r.exit = func(c int) { code = c }
+	r.SetupRoot(cmd).Setup(cmd,
+		scheduler.Module,
+		scheduler.Option(func() scheduler.Job {
+			return scheduler.Job{
+				Name:     "count",
+				Schedule: "@yearly",
+				Run: func(ctx context.Context) error {
+					runs++
+					return nil
+				},
+			}
+		}),
+		scheduler.Option(func() scheduler.Job {
+			return scheduler.Job{
+				Name:     "panic",
+				Schedule: "@yearly",
+				Run: func(ctx context.Context) error {
+					panic("boom")
+				},
+			}
+		}),
+	)
+
+	cmd.SetArgs([]string{"jobs", "run", "count"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, 1, runs)
+
+	cmd.SetArgs([]string{"jobs", "run", "panic"})
+	require.Error(t, cmd.Execute())
+	assert.Equal(t, 70, code)
+
+	cmd.SetArgs([]string{"jobs", "run", "missing"})
+	require.Error(t, cmd.Execute())
+	assert.Equal(t, 66, code)
+}
+
+func TestJobsRun_WithoutScheduler(t *testing.T) {
+	var code int
+
+	cmd := &cobra.Command{
+		Use:              "jobs",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	r := NewRunner("jobs")
+	r.exit = func(c int) { code = c }
+	r.SetupRoot(cmd).Setup(cmd)
+
+	cmd.SetArgs([]string{"jobs", "run", "missing"})
+	require.Error(t, cmd.Execute())
+	assert.Equal(t, 66, code)
+}
+
+type fakeScheduler struct {
+	scheduler.Scheduler
+	jobs    []string
+	started bool
+}
+
+func (s *fakeScheduler) Jobs() []string {
+	return s.jobs
+}
+
+func (s *fakeScheduler) Start(context.Context) error {
+	s.started = true
+	return nil
+}
+
+func (s *fakeScheduler) Stop(context.Context) error {
+	return nil
+}
+
+func TestStartScheduler(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		scheduler *fakeScheduler
+		task      TaskFunc
+		started   bool
+	}{
+		{name: "jobs", scheduler: &fakeScheduler{jobs: []string{"count"}}, started: true},
+		{name: "no jobs", scheduler: &fakeScheduler{}},
+		{name: "task", scheduler: &fakeScheduler{jobs: []string{"count"}}, task: func(context.Context) error { return nil }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lc := fxtest.NewLifecycle(t)
+			startScheduler(schedulerParams{Lifecycle: lc, Scheduler: tc.scheduler, Task: tc.task})
+			lc.RequireStart().RequireStop()
+			assert.Equal(t, tc.started, tc.scheduler.started)
+		})
+	}
+
+	// Apps without the scheduler module do not start a scheduler
+	lc := fxtest.NewLifecycle(t)
+	startScheduler(schedulerParams{Lifecycle: lc})
+	lc.RequireStart().RequireStop()
+}
+
+func TestWithCommands(t *testing.T) {
+	type database struct {
+		version string
+	}
+
+	var migrated string
+
+	cmd := &cobra.Command{
+		Use:              "contrib",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	r := NewRunner("contrib").WithExit(func(int) {}).WithCommands(Command{
+		Parent: "db",
+		Use:    "migrate VERSION",
+		Short:  "migrate the database",
+		Args:   cobra.ExactArgs(1),
+		Flags: func(flags *pflag.FlagSet) {
+			flags.Bool("dry-run", false, "do not apply the migration")
+		},
+		Run: func(ctx context.Context, cmd *cobra.Command, args Args, db *database) error {
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			if !dryRun {
+				migrated = db.version + " -> " + args[0]
+			}
+			return nil
+		},
+	})
+	r.SetupRoot(cmd).Setup(cmd, fx.Provide(func() *database { return &database{version: "1"} }))
+
+	cmd.SetArgs([]string{"db", "migrate", "2"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "1 -> 2", migrated)
+
+	cmd.SetArgs([]string{"db", "migrate", "--dry-run", "3"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "1 -> 2", migrated)
+
+	cmd.SetArgs([]string{"db", "migrate"})
+	require.Error(t, cmd.Execute())
+}
+
+func TestCommandOption(t *testing.T) {
+	var pinged bool
+
+	// The module adds its command just by being included
+	module := fx.Module("db",
+		fx.Provide(func() *TestString { return &TestString{String: "db"} }),
+		CommandOption(func() Command {
+			return Command{
+				Parent: "db",
+				Use:    "ping",
+				Short:  "ping the database",
+				Args:   cobra.NoArgs,
+				Run: func(ctx context.Context, s *TestString) error {
+					pinged = s.String == "db"
+					return nil
+				},
+			}
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:              "contrib",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+	NewRunner("contrib").WithExit(func(int) {}).SetupRoot(cmd).Setup(cmd, module)
+
+	cmd.SetArgs([]string{"db", "ping"})
+	require.NoError(t, cmd.Execute())
+	assert.True(t, pinged)
+}
+
+func TestVersion(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:              "version",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	r := NewRunner("version")
+	r.SetupRoot(cmd).Setup(cmd)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"version", "--format", "json"})
+	require.NoError(t, cmd.Execute())
+
+	var info service.BuildInfo
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+	assert.Equal(t, r.build.Version, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+
+	var build service.BuildInfo
+	var version service.Version
+	require.NoError(t, r.inspect(cmd, nil, &build, &version))
+	assert.Equal(t, info.GoVersion, build.GoVersion)
+	assert.Equal(t, info.Version, version)
+}
+
+func TestDocs(t *testing.T) {
+	dir := t.TempDir()
+
+	cmd := &cobra.Command{
+		Use:              "docs",
+		TraverseChildren: true,
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	NewRunner("docs").SetupRoot(cmd).Setup(cmd, config.Option[TestConfig]("config"))
+
+	cmd.SetArgs([]string{"docs", "--format", "markdown", "--dir", dir})
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(filepath.Join(dir, "docs.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "DOCS_CONFIG_DEF=/pki/issue")
+	assert.Contains(t, string(data), "DOCS_LOGLEVEL=")
+
+	_, err = os.Stat(filepath.Join(dir, "docs_completion_zsh.md"))
+	require.NoError(t, err)
+}
+
+func TestSetupServices(t *testing.T) {
+	var events []string
+
+	service := func(name string, failStart bool) Service {
+		return Service{
+			Name: name,
+			Options: []fx.Option{
+				fx.Invoke(func(lc fx.Lifecycle, shutdowner fx.Shutdowner, n service.Name) {
+					lc.Append(fx.Hook{
+						OnStart: func(ctx context.Context) error {
+							if failStart {
+								return errors.Unavailablef("%s unavailable", n)
+							}
+							events = append(events, "start "+string(n))
+							if name == "second" {
+								return shutdowner.Shutdown()
+							}
+							return nil
+						},
+						OnStop: func(ctx context.Context) error {
+							events = append(events, "stop "+string(n))
+							return nil
+						},
+					})
+				}),
+			},
+		}
+	}
+
+	newCmd := func(services ...Service) (*cobra.Command, *int) {
+		cmd := &cobra.Command{
+			Use:           "services",
+			SilenceUsage:  true,
+			SilenceErrors: true,
+		}
+
+		var code int
+		r := NewRunner("services")
+		r.exit = func(c int) { code = c }
+		r.SetupRoot(cmd).SetupServices(cmd, services...)
+		cmd.SetArgs([]string{})
+		return cmd, &code
+	}
+
+	cmd, _ := newCmd(service("first", false), service("second", false))
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, []string{"start first", "start second", "stop second", "stop first"}, events)
+
+	events = nil
+	cmd, code := newCmd(service("first", false), service("second", false), service("third", true))
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "third unavailable")
+	assert.Equal(t, 69, *code)
+	assert.Equal(t, []string{"start first", "start second", "stop second", "stop first"}, events)
+}
+
+func TestSetupServicesCommands(t *testing.T) {
+	type missing struct{}
+
+	newCmd := func(services ...Service) *cobra.Command {
+		cmd := &cobra.Command{
+			Use:              "services",
+			TraverseChildren: true,
+			SilenceUsage:     true,
+			SilenceErrors:    true,
+		}
+
+		NewRunner("services").SetupRoot(cmd).SetupServices(cmd, services...)
+		return cmd
+	}
+
+	run := func(cmd *cobra.Command, args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	first := Service{Name: "first", Options: []fx.Option{config.Option[TestString]("string")}}
+	second := Service{Name: "second", Options: []fx.Option{config.Option[TestString]("string")}}
+
+	out, err := run(newCmd(first, second), "init")
+	require.NoError(t, err)
+	assert.Regexp(t, `(?s)^# first\n.*FIRST_STRING_STRING=.*# second\n.*SECOND_STRING_STRING=`, out)
+
+	out, err = run(newCmd(first, second), "check")
+	require.NoError(t, err)
+	assert.Equal(t, "# first\nok\n# second\nok\n", out)
+
+	broken := Service{Name: "broken", Options: []fx.Option{fx.Invoke(func(*missing) {})}}
+	out, err = run(newCmd(first, broken), "check")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service broken")
+	assert.Equal(t, 78, errors.ExitCode(err))
+	assert.Contains(t, out, "hint: nothing provides *cmd.missing")
+
+	// Each service has its own dependency graph
+	out, err = run(newCmd(first, second), "second", "graph")
+	require.NoError(t, err)
+	assert.Contains(t, out, "digraph")
+
+	dir := t.TempDir()
+	_, err = run(newCmd(first, second), "docs", "--format", "markdown", "--dir", dir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "services.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "FIRST_STRING_STRING")
+	assert.Contains(t, string(data), "SECOND_STRING_STRING")
+
+	for _, name := range []string{"services_first_env_lint.md", "services_second_graph.md", "services_version.md"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestForceExitOnSignal(t *testing.T) {
+	// Keep the signals from terminating the test process if they arrive before the app listens for them
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	forced := make(chan int, 1)
+	osExit = func(code int) { forced <- code }
+	t.Cleanup(func() { osExit = os.Exit })
+
+	// The first signal stops the app, whose stop hook hangs until the second signal forces the exit
+	module := fx.Invoke(func(lifecycle fx.Lifecycle) {
+		lifecycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				return syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			},
+			OnStop: func(context.Context) error {
+				time.Sleep(100 * time.Millisecond)
+				if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+					return err
+				}
+
+				select {
+				case code := <-forced:
+					forced <- code
+				case <-time.After(5 * time.Second):
+				}
+				return nil
+			},
+		})
+	})
+
+	// Execute reports exit codes without exiting, which must not stop the forced exit
+	execute("test", []string{}, func(r *Runner, cmd *cobra.Command) {
+		r.WithExitCodes(map[string]int{errors.ECANCELED: 3}).Setup(cmd, module)
+	})
+
+	select {
+	case code := <-forced:
+		assert.Equal(t, 3, code)
+	default:
+		t.Error("did not exit")
+	}
+}
+
+func TestReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".env.reload")
+	require.NoError(t, os.WriteFile(file, []byte("RELOAD_STRING=before\n"), 0o600))
+	t.Cleanup(func() { os.Unsetenv("RELOAD_STRING") })
+
+	reloaded := make(chan string, 1)
+
+	cmd := &cobra.Command{
+		Use:           "reload",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	NewRunner("reload").SetupRoot(cmd).Setup(cmd,
+		config.Option[TestString](),
+		ReloadHookOption(func(cfg config.Provider) ReloadHook {
+			return ReloadHook{
+				Name: "test",
+				OnReload: func(ctx context.Context) error {
+					c, err := cfg.Get(ctx, "")
+					if err != nil {
+						return err
+					}
+					reloaded <- c.(*TestString).String
+					return nil
+				},
+			}
+		}),
+		Task(func(ctx context.Context, s TestString) error {
+			assert.Equal(t, "before", s.String)
+
+			require.NoError(t, os.WriteFile(file, []byte("RELOAD_STRING=after\n"), 0o600))
+			require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+			select {
+			case value := <-reloaded:
+				assert.Equal(t, "after", value)
+			case <-time.After(5 * time.Second):
+				t.Error("not reloaded")
+			}
+
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{"--config", file})
+	require.NoError(t, cmd.Execute())
+}
@@ -0,0 +1,186 @@
+// Package cmdtest runs commands set up by a cmd.Runner in-process, for testing
+package cmdtest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	stdlog "log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/cmd"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.uber.org/fx"
+)
+
+// DefaultTimeout is the time a command may run for unless overridden using WithTimeout
+const DefaultTimeout = 30 * time.Second
+
+// Harness runs the commands of a cmd.Runner in-process
+type Harness struct {
+	t        testing.TB
+	prefix   string
+	options  []fx.Option
+	commands []cmd.Command
+	env      map[string]string
+	timeout  time.Duration
+	stdin    io.Reader
+}
+
+// Option configures a Harness
+type Option func(h *Harness)
+
+// WithEnv sets the given environment variables for the duration of the test. As the environment is shared by the
+// process, tests using it must not run in parallel.
+func WithEnv(env map[string]string) Option {
+	return func(h *Harness) {
+		for k, v := range env {
+			h.env[k] = v
+		}
+	}
+}
+
+// WithTimeout sets the time after which the command context is canceled, which stops a long-running app
+func WithTimeout(timeout time.Duration) Option {
+	return func(h *Harness) {
+		h.timeout = timeout
+	}
+}
+
+// WithStdin sets the standard input of the command
+func WithStdin(stdin io.Reader) Option {
+	return func(h *Harness) {
+		h.stdin = stdin
+	}
+}
+
+// Populate populates the targets from the app built by the command, as fx.Populate
+func Populate(targets ...any) Option {
+	return func(h *Harness) {
+		h.options = append(h.options, fx.Populate(targets...))
+	}
+}
+
+// WithOptions adds fx options to the app built by the command (e.g., to replace a dependency with a fake)
+func WithOptions(options ...fx.Option) Option {
+	return func(h *Harness) {
+		h.options = append(h.options, options...)
+	}
+}
+
+// WithCommands adds contributed commands to the Runner, as cmd.Runner.WithCommands
+func WithCommands(commands ...cmd.Command) Option {
+	return func(h *Harness) {
+		h.commands = append(h.commands, commands...)
+	}
+}
+
+// New returns a Harness running the commands of a Runner with the given prefix, set up with the given module
+func New(t testing.TB, prefix string, module fx.Option, opts ...Option) *Harness {
+	h := &Harness{
+		t:       t,
+		prefix:  prefix,
+		options: []fx.Option{module},
+		env:     make(map[string]string),
+		timeout: DefaultTimeout,
+		stdin:   strings.NewReader(""),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Result is the outcome of running a command
+type Result struct {
+	Stdout   string
+	Stderr   string
+	Logs     []*logrus.Entry
+	Err      error
+	ExitCode int
+}
+
+// Run runs the command with the given arguments (e.g., `init`) and returns the Result. Every run sets up a new root
+// command, so flags do not carry over between runs.
+func (h *Harness) Run(args ...string) Result {
+	h.t.Helper()
+
+	// The flag defaults are read from the environment when the root command is set up
+	for k, v := range h.env {
+		h.t.Setenv(k, v)
+	}
+
+	logs := captureLogs(h.t)
+
+	var stdout, stderr bytes.Buffer
+	root := &cobra.Command{
+		Use:              h.prefix,
+		TraverseChildren: true,
+		SilenceUsage:     true,
+	}
+	root.SetArgs(args)
+	root.SetIn(h.stdin)
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+
+	exitCode := -1
+	r := cmd.NewRunner(h.prefix).WithExit(func(code int) {
+		exitCode = code
+	}).WithCommands(h.commands...)
+	r.SetupRoot(root).Setup(root, h.options...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	err := root.ExecuteContext(ctx)
+	if err == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = errors.Timeoutf("command did not complete within %s", h.timeout)
+	}
+
+	if exitCode < 0 {
+		exitCode = r.ExitCode(err)
+	}
+
+	return Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Logs:     logs.AllEntries(),
+		Err:      err,
+		ExitCode: exitCode,
+	}
+}
+
+// captureLogs records the entries logged to the standard logger, restoring the global logging state when the test
+// completes
+func captureLogs(t testing.TB) *test.Hook {
+	logger := logrus.StandardLogger()
+
+	out := logger.Out
+	level := logger.GetLevel()
+	formatter := logger.Formatter
+	// Keep the existing hooks (e.g., log.ErrorHook) firing while capturing
+	captured := make(logrus.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		captured[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+	hooks := logger.ReplaceHooks(captured)
+	stdOut := stdlog.Writer()
+
+	t.Cleanup(func() {
+		logger.SetOutput(out)
+		logger.SetLevel(level)
+		logger.SetFormatter(formatter)
+		logger.ReplaceHooks(hooks)
+		stdlog.SetOutput(stdOut)
+	})
+
+	logger.SetOutput(io.Discard)
+	return test.NewLocal(logger)
+}
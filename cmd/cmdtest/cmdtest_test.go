@@ -0,0 +1,124 @@
+package cmdtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/cmd"
+	"go.ketch.com/lib/orlop/v2/config"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.uber.org/fx"
+)
+
+type testConfig struct {
+	URL  string `config:"url,required"`
+	Pool int    `config:"pool,default=5"`
+}
+
+func TestHarness_Run(t *testing.T) {
+	module := config.Option[testConfig]("db")
+
+	for _, tt := range []struct {
+		name     string
+		args     []string
+		stdout   []string
+		exitCode int
+	}{
+		{
+			name:   "init",
+			args:   []string{"init"},
+			stdout: []string{"#HARNESS_DB_URL=# string", "HARNESS_DB_POOL=5"},
+		},
+		{
+			name:   "check",
+			args:   []string{"check"},
+			stdout: []string{"ok"},
+		},
+		{
+			name:     "lint",
+			args:     []string{"env", "lint", "--dir", t.TempDir()},
+			stdout:   []string{"local environment: HARNESS_DB_URL missing"},
+			exitCode: 64,
+		},
+		{
+			name:   "version",
+			args:   []string{"version"},
+			stdout: []string{"version:", "go version:"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			res := New(t, "harness", module).Run(tt.args...)
+
+			assert.Equal(t, tt.exitCode, res.ExitCode, res.Stderr)
+			for _, s := range tt.stdout {
+				assert.Contains(t, res.Stdout, s)
+			}
+		})
+	}
+}
+
+func TestHarness_Task(t *testing.T) {
+	var cfg testConfig
+
+	h := New(t, "harness", fx.Options(
+		config.Option[testConfig]("db"),
+		cmd.Task(func(ctx context.Context, cfg testConfig, l logging.Logger) error {
+			l.WithField("url", cfg.URL).Info("connecting")
+			if cfg.Pool == 0 {
+				return errors.Invalidf("pool must not be empty")
+			}
+			return nil
+		}),
+	), WithEnv(map[string]string{"HARNESS_DB_URL": "postgres://db"}), Populate(&cfg))
+
+	res := h.Run("--loglevel", "info")
+	require.NoError(t, res.Err)
+	assert.Equal(t, "postgres://db", cfg.URL)
+
+	var found bool
+	for _, entry := range res.Logs {
+		if entry.Message == "connecting" {
+			found = true
+			assert.Equal(t, "postgres://db", entry.Data["url"])
+		}
+	}
+	assert.True(t, found)
+
+	res = New(t, "harness", fx.Options(
+		config.Option[testConfig]("db"),
+		cmd.Task(func(ctx context.Context, cfg testConfig) error {
+			return errors.Invalidf("pool must not be empty")
+		}),
+	), WithEnv(map[string]string{"HARNESS_DB_URL": "postgres://db"})).Run()
+	require.Error(t, res.Err)
+	assert.Equal(t, 64, res.ExitCode)
+	assert.Contains(t, res.Stderr, "pool must not be empty")
+}
+
+func TestHarness_Timeout(t *testing.T) {
+	res := New(t, "harness", fx.Options(), WithTimeout(100*time.Millisecond)).Run()
+	require.Error(t, res.Err)
+	assert.True(t, errors.IsTimeout(res.Err))
+	assert.Equal(t, 75, res.ExitCode)
+}
+
+func TestHarness_WithCommands(t *testing.T) {
+	h := New(t, "harness", config.Option[testConfig]("db"), WithCommands(cmd.Command{
+		Parent: "db",
+		Use:    "ping",
+		Run: func(ctx context.Context, c *cobra.Command, cfg testConfig) error {
+			_, err := fmt.Fprintln(c.OutOrStdout(), "pool", cfg.Pool)
+			return err
+		},
+	}), WithEnv(map[string]string{"HARNESS_DB_URL": "postgres://db"}))
+
+	res := h.Run("db", "ping")
+	require.NoError(t, res.Err)
+	assert.Equal(t, "pool 5\n", res.Stdout)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.ketch.com/lib/orlop/v2/log"
+	"go.uber.org/fx"
+)
+
+// Command describes a subcommand contributed by a module, either provided using CommandOption or added to a Runner
+// using WithCommands
+type Command struct {
+	// Parent is the space-separated path of the parent command (e.g., `db`), which is created if it does not exist
+	Parent string
+
+	// Use is the one-line usage message, where the first word is the name of the command (e.g., `migrate VERSION`)
+	Use string
+
+	// Short is the short description shown in help
+	Short string
+
+	// Long is the long description shown in help
+	Long string
+
+	// Args validates the positional arguments, which the handler can depend on as Args
+	Args cobra.PositionalArgs
+
+	// Flags defines the flags of the command, which the handler can read from the injected *cobra.Command
+	Flags func(flags *pflag.FlagSet)
+
+	// Run is the handler, of the form `func(ctx context.Context, deps...) error`. It is run as a Task.
+	Run any
+}
+
+// Args are the positional arguments of a contributed Command
+type Args []string
+
+// CommandOption provides a Command from the given constructor, so including the module adds the command
+func CommandOption(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"commands"`),
+		),
+	)
+}
+
+type commandParams struct {
+	fx.In
+
+	Commands []Command `group:"commands"`
+}
+
+type commands []Command
+
+func newCommands(p commandParams) commands {
+	return p.Commands
+}
+
+// WithCommands adds Commands to those provided by the modules using CommandOption (e.g., for commands that are not
+// part of a module)
+func (r *Runner) WithCommands(commands ...Command) *Runner {
+	r.commands = append(r.commands, commands...)
+	return r
+}
+
+// addCommands adds the Commands given to WithCommands and those provided by the modules in the options to cmd. The
+// provided commands are read from the app graph without running its invokes.
+func (r *Runner) addCommands(cmd *cobra.Command, options ...fx.Option) {
+	var contributed commands
+	if err := r.inspect(cmd, options, &contributed); err != nil {
+		// The error is reported when a command builds the app, so it is not an error for the commands
+		log.WithError(err).Debug("could not read the provided commands")
+	}
+
+	for _, c := range append(r.commands[:len(r.commands):len(r.commands)], contributed...) {
+		parent := cmd
+		for _, name := range strings.Fields(c.Parent) {
+			parent = childCommand(parent, name)
+		}
+
+		name := strings.Fields(c.Use)
+		if len(name) == 0 || findCommand(parent, name[0]) != nil {
+			log.WithField("command", strings.TrimSpace(c.Parent+" "+c.Use)).Warn("skipping command, already exists")
+			continue
+		}
+
+		parent.AddCommand(r.contributedCommand(c, options...))
+	}
+}
+
+func (r *Runner) contributedCommand(c Command, options ...fx.Option) *cobra.Command {
+	contributedCmd := &cobra.Command{
+		Use:   c.Use,
+		Short: c.Short,
+		Long:  c.Long,
+		Args:  c.Args,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return r.runE(append(options[:len(options):len(options)], fx.Supply(Args(args)), Task(c.Run))...)(cmd, args)
+		},
+	}
+
+	if c.Flags != nil {
+		c.Flags(contributedCmd.Flags())
+	}
+
+	return contributedCmd
+}
+
+// childCommand returns the child of cmd with the given name, adding it if it does not exist
+func childCommand(cmd *cobra.Command, name string) *cobra.Command {
+	if child := findCommand(cmd, name); child != nil {
+		return child
+	}
+
+	child := &cobra.Command{
+		Use:  name,
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(child)
+
+	return child
+}
+
+func findCommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, child := range cmd.Commands() {
+		if child.Name() == name {
+			return child
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"go.ketch.com/lib/orlop/v2/config"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.uber.org/fx"
+)
+
+// registerCompletions adds shell completion of the values of the root flags
+func (r *Runner) registerCompletions(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("env", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return knownEnvironments("."), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = cmd.RegisterFlagCompletionFunc("config", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		files, err := filepath.Glob(filepath.Join(filepath.Dir(toComplete), ".env*"))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		// Glob drops the leading ./ that the user may have typed
+		if strings.HasPrefix(toComplete, "./") {
+			for n, file := range files {
+				files[n] = "./" + file
+			}
+		}
+
+		return files, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// knownEnvironments returns the standard environments and those with a dotenv file in the given directory
+func knownEnvironments(dir string) []string {
+	known := map[string]bool{
+		"local":                   true,
+		env.Test().String():       true,
+		env.Production().String(): true,
+	}
+
+	if environments, err := dotenvEnvironments(dir); err == nil {
+		for _, e := range environments {
+			if !e.IsLocal() {
+				known[e.String()] = true
+			}
+		}
+	}
+
+	var names []string
+	for name := range known {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// configVars returns a function listing the config environment variables of the app built from the options
+func (r *Runner) configVars(options ...fx.Option) func(cmd *cobra.Command) ([]string, error) {
+	return func(cmd *cobra.Command) ([]string, error) {
+		var cfgMgr config.Provider
+		if err := r.inspect(cmd, options, &cfgMgr); err != nil {
+			return nil, err
+		}
+
+		return cfgMgr.List(cmd.Context())
+	}
+}
+
+// docsCommand returns the `docs` command, which generates documentation for the commands and the config environment
+// variables listed by vars
+func (r *Runner) docsCommand(vars func(cmd *cobra.Command) ([]string, error)) *cobra.Command {
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "generate man pages or Markdown documentation for the commands and exits",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+
+			dir, err := cmd.Flags().GetString("dir")
+			if err != nil {
+				return err
+			}
+
+			configVars, err := vars(cmd)
+			if err != nil {
+				return err
+			}
+
+			root := cmd.Root()
+			root.DisableAutoGenTag = true
+			root.Long = environmentDocs(root.Long, r.prefix, configVars)
+
+			if err = os.MkdirAll(dir, 0o755); err != nil {
+				return errors.Convert(err)
+			}
+
+			switch format {
+			case "man":
+				return doc.GenManTree(root, &doc.GenManHeader{
+					Title:   strings.ToUpper(root.Name()),
+					Section: "1",
+					Source:  string(r.build.Version),
+				}, dir)
+
+			case "markdown":
+				return doc.GenMarkdownTree(root, dir)
+
+			default:
+				return errors.Invalidf("unsupported format '%s'", format)
+			}
+		},
+	}
+	docsCmd.Flags().String("format", "man", "specifies the output format (man or markdown)")
+	docsCmd.Flags().String("dir", ".", "specifies the directory to write the documentation to")
+
+	return docsCmd
+}
+
+// environmentDocs appends the environment variables read by the Runner and the registered configs to the description
+func environmentDocs(description string, prefix string, vars []string) string {
+	vars = append(vars,
+		config.GetPrefixKey(prefix, env.EnvironmentKey)+"=# string",
+		config.GetPrefixKey(prefix, "loglevel")+"=# string",
+	)
+	sort.Strings(vars)
+
+	var sb strings.Builder
+	if len(description) > 0 {
+		sb.WriteString(description)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("Environment variables (with defaults):\n\n")
+	for _, v := range vars {
+		fmt.Fprintf(&sb, "    %s\n", v)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+	"go.uber.org/fx"
+)
+
+// envCommand returns the `env` command for maintaining dotenv files
+func (r *Runner) envCommand(options ...fx.Option) *cobra.Command {
+	envCmd := &cobra.Command{
+		Use:   "env",
+		Short: "maintain dotenv files",
+		Args:  cobra.NoArgs,
+	}
+	envCmd.PersistentFlags().String("key-file", "", "specifies a file containing the dotenv key (defaults to "+env.KeyEnvironmentKey+" or "+env.KeyFileEnvironmentKey+")")
+
+	envCmd.AddCommand(&cobra.Command{
+		Use:   "keygen",
+		Short: "output a new dotenv key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := env.NewKey()
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), key.String())
+			return err
+		},
+	})
+
+	encryptCmd := &cobra.Command{
+		Use:   "encrypt FILE",
+		Short: "encrypt a dotenv file to FILE" + env.EncryptedExtension,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := envKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+			if len(output) == 0 {
+				output = args[0] + env.EncryptedExtension
+			}
+
+			plaintext, err := os.ReadFile(args[0])
+			if err != nil {
+				return errors.Convert(err)
+			}
+
+			return writeEncrypted(key, output, plaintext)
+		},
+	}
+	encryptCmd.Flags().String("output", "", "specifies the encrypted output file (defaults to FILE"+env.EncryptedExtension+")")
+	envCmd.AddCommand(encryptCmd)
+
+	envCmd.AddCommand(&cobra.Command{
+		Use:   "decrypt FILE" + env.EncryptedExtension,
+		Short: "output the decrypted contents of an encrypted dotenv file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := envKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			plaintext, err := readEncrypted(key, args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = cmd.OutOrStdout().Write(plaintext)
+			return err
+		},
+	})
+
+	editCmd := &cobra.Command{
+		Use:   "edit FILE" + env.EncryptedExtension,
+		Short: "edit an encrypted dotenv file using $EDITOR",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := envKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			var plaintext []byte
+			if _, err = os.Stat(args[0]); err == nil {
+				if plaintext, err = readEncrypted(key, args[0]); err != nil {
+					return err
+				}
+			}
+
+			edited, err := editInPlace(cmd, filepath.Base(strings.TrimSuffix(args[0], env.EncryptedExtension)), plaintext)
+			if err != nil {
+				return err
+			}
+
+			return writeEncrypted(key, args[0], edited)
+		},
+	}
+	editCmd.Flags().String("unsafe-tmpdir", "", "specifies a directory for the plaintext copy if no memory-backed directory is available (the plaintext is written to disk)")
+	envCmd.AddCommand(editCmd)
+
+	envCmd.AddCommand(r.lintCommand(options...))
+
+	return envCmd
+}
+
+func envKey(cmd *cobra.Command) (env.Key, error) {
+	keyFile, err := cmd.Flags().GetString("key-file")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keyFile) > 0 {
+		return env.ReadKey(keyFile)
+	}
+
+	return env.LoadKey()
+}
+
+func readEncrypted(key env.Key, file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+
+	return key.Decrypt(data)
+}
+
+func writeEncrypted(key env.Key, file string, plaintext []byte) error {
+	data, err := key.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
+
+// memoryTempDir is the memory-backed directory for the plaintext copy edited by `env edit`
+var memoryTempDir = "/dev/shm"
+
+// editTempDir returns the directory for the plaintext copy edited by `env edit`. The plaintext must not reach a disk,
+// so it must be memory-backed unless the user explicitly passes --unsafe-tmpdir.
+func editTempDir(cmd *cobra.Command) (string, error) {
+	if fi, err := os.Stat(memoryTempDir); err == nil && fi.IsDir() {
+		return memoryTempDir, nil
+	}
+
+	dir, err := cmd.Flags().GetString("unsafe-tmpdir")
+	if err != nil {
+		return "", err
+	}
+	if len(dir) == 0 {
+		return "", errors.Configurationf("no memory-backed directory (%s) is available for the plaintext, pass --unsafe-tmpdir to write it to disk", memoryTempDir)
+	}
+
+	log.WithField("dir", dir).Warn("UNSAFE: the decrypted dotenv file is written to disk while editing")
+	return dir, nil
+}
+
+// editInPlace runs $EDITOR on a private temporary copy of the plaintext, removing it afterward
+func editInPlace(cmd *cobra.Command, name string, plaintext []byte) ([]byte, error) {
+	editor := os.Getenv("VISUAL")
+	if len(editor) == 0 {
+		editor = os.Getenv("EDITOR")
+	}
+	if len(editor) == 0 {
+		return nil, errors.Configurationf("VISUAL or EDITOR must be set to edit dotenv files")
+	}
+
+	dir, err := editTempDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	defer os.Remove(f.Name())
+
+	// Make sure only the owner can read the copy before writing the plaintext to it
+	if err = f.Chmod(0o600); err != nil {
+		f.Close()
+		return nil, errors.Convert(err)
+	}
+	if fi, err := f.Stat(); err != nil || fi.Mode().Perm() != 0o600 {
+		f.Close()
+		return nil, errors.Forbiddenf("could not restrict the permissions of '%s'", f.Name())
+	}
+
+	if _, err = f.Write(plaintext); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err = f.Close(); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Fields(editor)
+	c := exec.CommandContext(cmd.Context(), parts[0], append(parts[1:], f.Name())...)
+	c.Stdin = cmd.InOrStdin()
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+
+	if err = c.Run(); err != nil {
+		return nil, errors.Wrap(err, "editor failed")
+	}
+
+	return os.ReadFile(f.Name())
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+	"go.uber.org/fx"
+)
+
+type exitCoder interface {
+	ExitCode() int
+}
+
+// exitError is returned when the app requested a specific exit code when shutting down
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e exitError) ExitCode() int {
+	return int(e)
+}
+
+// WithExitCodes overrides the process exit codes used for the given error codes
+func (r *Runner) WithExitCodes(codes map[string]int) *Runner {
+	if r.exitCodes == nil {
+		r.exitCodes = make(map[string]int)
+	}
+
+	for code, exitCode := range codes {
+		r.exitCodes[code] = exitCode
+	}
+
+	return r
+}
+
+// WithExit replaces os.Exit as the function called with the exit code when the app fails to start or stop cleanly,
+// or is shut down with a non-zero exit code
+func (r *Runner) WithExit(exit func(code int)) *Runner {
+	r.exit = exit
+	return r
+}
+
+// ExitCode returns the process exit code for the error. Errors providing an ExitCode method (e.g., *exec.ExitError)
+// use that code, otherwise the code is derived from the error code using the overrides given to WithExitCodes and
+// then errors.ExitCode.
+func (r *Runner) ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var ec exitCoder
+	if errors.As(err, &ec) && ec.ExitCode() > 0 {
+		return ec.ExitCode()
+	}
+
+	if exitCode, ok := r.exitCodes[errors.Code(err)]; ok {
+		return exitCode
+	}
+
+	return errors.ExitCode(err)
+}
+
+// Execute loads config and then executes the given runner, returning the process exit code rather than exiting
+func Execute(prefix string, module fx.Option) int {
+	return execute(prefix, nil, func(r *Runner, cmd *cobra.Command) {
+		r.Setup(cmd, module)
+	})
+}
+
+// Run loads config and then executes the given runner, exiting with the exit code for any error
+func Run(prefix string, module fx.Option) {
+	if code := Execute(prefix, module); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// ExecuteServices loads config and then runs the given services in the same process, returning the process exit
+// code rather than exiting
+func ExecuteServices(prefix string, services ...Service) int {
+	return execute(prefix, nil, func(r *Runner, cmd *cobra.Command) {
+		r.SetupServices(cmd, services...)
+	})
+}
+
+// RunServices loads config and then runs the given services in the same process, exiting with the exit code for
+// any error
+func RunServices(prefix string, services ...Service) {
+	if code := ExecuteServices(prefix, services...); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// execute executes the command with the given arguments (or those of the process, if nil) and returns the exit code
+func execute(prefix string, args []string, setup func(r *Runner, cmd *cobra.Command)) int {
+	var cmd = &cobra.Command{
+		Use:              prefix,
+		TraverseChildren: true,
+		SilenceUsage:     true,
+	}
+	if args != nil {
+		cmd.SetArgs(args)
+	}
+
+	r := NewRunner(prefix).WithExit(func(int) {})
+	setup(r.SetupRoot(cmd), cmd)
+	usageErrors(cmd)
+
+	err := cmd.Execute()
+
+	// An exit code requested when shutting down is not a failure
+	var ee exitError
+	if err != nil && !errors.As(err, &ee) {
+		log.WithError(err).Log(logrus.FatalLevel, err)
+	}
+
+	return r.ExitCode(err)
+}
+
+// usageErrors makes the errors of the argument validation of cmd and its subcommands EINVALID, so they exit with the
+// usage exit code (64), as flag errors do (see SetupRoot)
+func usageErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return errors.Invalid(err)
+			}
+			return nil
+		}
+	}
+
+	for _, c := range cmd.Commands() {
+		usageErrors(c)
+	}
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.uber.org/fx"
+)
+
+// checkCommand returns the `check` command, which validates the dependency graph without running any constructors
+func (r *Runner) checkCommand(options ...fx.Option) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "validate that every dependency of the app is provided and exits",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// ValidateApp runs in dry-run mode, so no constructors or lifecycle hooks are called
+			err := fx.ValidateApp(
+				fx.NopLogger,
+				r.appOptions(cmd, logging.FatalLevel, options...),
+			)
+			if err != nil {
+				for _, hint := range remediationHints(err) {
+					fmt.Fprintf(cmd.OutOrStdout(), "hint: %s\n", hint)
+				}
+
+				return errors.Configuration(err)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return err
+		},
+	}
+}
+
+var (
+	missingTypesPattern    = regexp.MustCompile(`missing types?: (.+)$`)
+	alreadyProvidedPattern = regexp.MustCompile(`cannot provide (.+) from \[\d+\]: already provided by (.+)$`)
+)
+
+// remediationHints returns suggestions for fixing the given app validation error
+func remediationHints(err error) []string {
+	var hints []string
+	msg := err.Error()
+
+	if m := missingTypesPattern.FindStringSubmatch(msg); m != nil {
+		for _, t := range strings.Split(m[1], "; ") {
+			t = strings.TrimSpace(strings.SplitN(t, " (", 2)[0])
+			hints = append(hints, fmt.Sprintf("nothing provides %s: add a constructor with fx.Provide, include the "+
+				"module that provides it, or register it with config.Option if it is a config", t))
+		}
+	}
+
+	if m := alreadyProvidedPattern.FindStringSubmatch(msg); m != nil {
+		hints = append(hints, fmt.Sprintf("%s is provided more than once (already provided by %s): remove one of the "+
+			"constructors, or distinguish them with a name tag or a value group", m[1], m[2]))
+	}
+
+	if strings.Contains(msg, "cycle detected") {
+		hints = append(hints, "constructors depend on each other: break the cycle by depending on an interface, or "+
+			"by resolving one side of it in a lifecycle hook")
+	}
+
+	return hints
+}
+
+// graphCommand returns the `graph` command, which outputs the dependency graph of the app
+func (r *Runner) graphCommand(options ...fx.Option) *cobra.Command {
+	graphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "output the dependency graph of the app and exits",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+
+			var dot fx.DotGraph
+			if err = r.inspect(cmd, options, &dot); err != nil {
+				return err
+			}
+
+			switch format {
+			case "dot":
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), dot)
+				return err
+
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				g, err := parseDotGraph(dot)
+				if err != nil {
+					return err
+				}
+
+				return enc.Encode(g)
+
+			default:
+				return errors.Invalidf("unsupported format '%s'", format)
+			}
+		},
+	}
+	graphCmd.Flags().String("format", "dot", "specifies the output format (dot or json)")
+
+	return graphCmd
+}
+
+type graph struct {
+	Constructors []*graphConstructor `json:"constructors"`
+	Groups       []*graphGroup       `json:"groups"`
+}
+
+type graphConstructor struct {
+	Name    string       `json:"name"`
+	Package string       `json:"package"`
+	Params  []graphParam `json:"params"`
+	Results []string     `json:"results"`
+}
+
+type graphParam struct {
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+type graphGroup struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Results []string `json:"results"`
+}
+
+var (
+	dotClusterPattern     = regexp.MustCompile(`^subgraph cluster_(\d+) {$`)
+	dotPackagePattern     = regexp.MustCompile(`^label = "(.*)";$`)
+	dotConstructorPattern = regexp.MustCompile(`^constructor_\d+ \[shape=plaintext label="(.*)"];$`)
+	dotResultPattern      = regexp.MustCompile(`^"(.*)" \[label=<.*>];$`)
+	dotParamPattern       = regexp.MustCompile(`^constructor_(\d+) -> "(.*)" \[ltail=cluster_\d+( style=dashed)?];$`)
+	dotGroupPattern       = regexp.MustCompile(`^"(\[type=.* group=.*])" \[shape=diamond .*];$`)
+	dotGroupResultPattern = regexp.MustCompile(`^"(\[type=.* group=.*])" -> "(.*)";$`)
+	dotGroupNodePattern   = regexp.MustCompile(`^\[type=(.*) group=(.*)]$`)
+	dotGroupIndexPattern  = regexp.MustCompile(`]\d+$`)
+)
+
+// parseDotGraph converts the DOT output of dig into a graph of constructors and value groups. dig does not expose the
+// graph otherwise, so the output is parsed line by line, and it returns an error if none of it could be parsed (e.g.,
+// if a later version of dig changed its format) rather than an empty graph.
+func parseDotGraph(dot fx.DotGraph) (graph, error) {
+	g := graph{
+		Constructors: []*graphConstructor{},
+		Groups:       []*graphGroup{},
+	}
+
+	constructors := make(map[string]*graphConstructor)
+	groups := make(map[string]*graphGroup)
+
+	var current *graphConstructor
+	scanner := bufio.NewScanner(strings.NewReader(string(dot)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "}":
+			current = nil
+
+		case dotClusterPattern.MatchString(line):
+			current = &graphConstructor{
+				Params:  []graphParam{},
+				Results: []string{},
+			}
+			constructors[dotClusterPattern.FindStringSubmatch(line)[1]] = current
+			g.Constructors = append(g.Constructors, current)
+
+		case current != nil && dotPackagePattern.MatchString(line):
+			current.Package = dotPackagePattern.FindStringSubmatch(line)[1]
+
+		case current != nil && dotConstructorPattern.MatchString(line):
+			current.Name = dotConstructorPattern.FindStringSubmatch(line)[1]
+
+		case current != nil && dotResultPattern.MatchString(line):
+			result := dotResultPattern.FindStringSubmatch(line)[1]
+			current.Results = append(current.Results, dotGroupIndexPattern.ReplaceAllString(result, "]"))
+
+		case dotParamPattern.MatchString(line):
+			m := dotParamPattern.FindStringSubmatch(line)
+			if c, ok := constructors[m[1]]; ok {
+				c.Params = append(c.Params, graphParam{
+					Type:     paramType(m[2]),
+					Optional: len(m[3]) > 0,
+				})
+			}
+
+		case dotGroupPattern.MatchString(line):
+			node := dotGroupPattern.FindStringSubmatch(line)[1]
+			m := dotGroupNodePattern.FindStringSubmatch(node)
+			group := &graphGroup{
+				Name:    m[2],
+				Type:    m[1],
+				Results: []string{},
+			}
+			groups[node] = group
+			g.Groups = append(g.Groups, group)
+
+		case dotGroupResultPattern.MatchString(line):
+			m := dotGroupResultPattern.FindStringSubmatch(line)
+			if group, ok := groups[m[1]]; ok {
+				group.Results = append(group.Results, dotGroupIndexPattern.ReplaceAllString(m[2], "]"))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return graph{}, errors.Internal(errors.Wrap(err, "could not parse the dependency graph"))
+	}
+
+	if len(g.Constructors) == 0 && len(strings.TrimSpace(string(dot))) > 0 {
+		return graph{}, errors.Internalf("could not parse the dependency graph: no constructors found in the DOT " +
+			"output, use --format dot instead")
+	}
+
+	return g, nil
+}
+
+// paramType converts a group node name (e.g., `[type=T group=g]`) into the form used by results (`T[group=g]`)
+func paramType(node string) string {
+	if m := dotGroupNodePattern.FindStringSubmatch(node); m != nil {
+		return fmt.Sprintf("%s[group=%s]", m[1], m[2])
+	}
+
+	return node
+}
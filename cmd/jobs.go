@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/scheduler"
+	"go.uber.org/fx"
+)
+
+type schedulerParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Scheduler scheduler.Scheduler `optional:"true"`
+	Task      TaskFunc            `optional:"true"`
+}
+
+// startScheduler runs the scheduled jobs along with the app, unless the app is running a one-shot task or has no
+// jobs to schedule
+func startScheduler(p schedulerParams) {
+	if p.Task != nil || p.Scheduler == nil || len(p.Scheduler.Jobs()) == 0 {
+		return
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: p.Scheduler.Start,
+		OnStop:  p.Scheduler.Stop,
+	})
+}
+
+type jobsParams struct {
+	fx.In
+
+	Scheduler scheduler.Scheduler `optional:"true"`
+}
+
+// jobsCommand returns the `jobs` command for running scheduled jobs on demand
+func (r *Runner) jobsCommand(options ...fx.Option) *cobra.Command {
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "manage scheduled jobs",
+		Args:  cobra.NoArgs,
+	}
+
+	jobsCmd.AddCommand(&cobra.Command{
+		Use:   "run NAME",
+		Short: "run a scheduled job once and exits",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Run the job as a one-shot task, so the app is started and stopped around it
+			task := Task(func(ctx context.Context, p jobsParams) error {
+				if p.Scheduler == nil {
+					return errors.NotFoundf("job '%s' not found", name)
+				}
+				return p.Scheduler.Run(ctx, name)
+			})
+
+			return r.runE(append(options[:len(options):len(options)], task)...)(cmd, args)
+		},
+	})
+
+	return jobsCmd
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/config"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.ketch.com/lib/orlop/v2/service"
+	"go.uber.org/fx"
+)
+
+// LifecycleConfig configures how the application is started and stopped
+type LifecycleConfig struct {
+	StartTimeout      time.Duration `config:"start_timeout,default=15s"`
+	StopTimeout       time.Duration `config:"stop_timeout,default=15s"`
+	DrainDelay        time.Duration `config:"drain_delay,default=0s"`
+	ForceExit         bool          `config:"force_exit,default=true"`
+	SlowHookThreshold time.Duration `config:"slow_hook_threshold,default=5s"`
+}
+
+// ShutdownHook is a drain step run, in Order, after a shutdown signal and before the lifecycle OnStop hooks
+type ShutdownHook struct {
+	Name       string
+	Order      int
+	OnShutdown func(ctx context.Context) error
+}
+
+// ShutdownHookOption provides a ShutdownHook from the given constructor
+func ShutdownHookOption(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"shutdown"`),
+		),
+	)
+}
+
+type runParams struct {
+	fx.In
+
+	Logger      logging.Logger
+	Build       service.BuildInfo
+	Config      config.Provider
+	Hooks       []ShutdownHook `group:"shutdown"`
+	ReloadHooks []ReloadHook   `group:"reload"`
+	Task        TaskFunc       `optional:"true"`
+}
+
+// lifecycleConfig loads the LifecycleConfig ahead of the app, as it determines how the app is started
+func (r *Runner) lifecycleConfig(ctx context.Context) (LifecycleConfig, error) {
+	var cfg LifecycleConfig
+
+	p := config.New(config.Params{
+		Environ: env.NewEnviron(service.Name(r.prefix)),
+		Prefix:  service.Name(r.prefix),
+		Defs: []config.Definition{
+			{
+				Name:   "lifecycle",
+				Config: &cfg,
+			},
+		},
+	})
+
+	_, err := p.Get(ctx, "lifecycle")
+	return cfg, err
+}
+
+// run starts the app, waits for a shutdown signal (or for the task to complete), drains and then stops the app,
+// returning the exit code requested by the signal. While running, a SIGHUP reloads the environment and config.
+func (r *Runner) run(cmd *cobra.Command, app *fx.App, cfg LifecycleConfig, p runParams) (int, error) {
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+
+	if err := app.Start(startCtx); err != nil {
+		return 1, err
+	}
+
+	p.Logger.WithFields(p.Build.Fields()...).Info("started")
+
+	stopReload := r.reloadOnSignal(cmd, p)
+
+	var sig fx.ShutdownSignal
+	var taskErr error
+	if p.Task != nil {
+		sig, taskErr = runTask(cmd.Context(), app, p)
+	} else {
+		// The command context being canceled (e.g., by a test timeout) shuts the app down like a signal
+		select {
+		case sig = <-app.Wait():
+		case <-cmd.Context().Done():
+		}
+	}
+
+	stopReload()
+
+	logger := p.Logger.WithField("signal", sig.Signal)
+	logger.Info("shutting down")
+
+	done := make(chan struct{})
+	defer close(done)
+
+	if cfg.ForceExit {
+		go r.forceExitOnSignal(logger, done)
+	}
+
+	// A task does not serve traffic, so there is nothing to drain
+	if cfg.DrainDelay > 0 && p.Task == nil {
+		logger.WithField("delay", cfg.DrainDelay).Info("waiting for drain")
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+
+	runShutdownHooks(stopCtx, logger, cfg, p.Hooks)
+
+	if err := app.Stop(stopCtx); err != nil {
+		return 1, err
+	}
+
+	logger.Info("shut down")
+
+	if taskErr != nil {
+		return 1, taskErr
+	}
+
+	return sig.ExitCode, nil
+}
+
+func runShutdownHooks(ctx context.Context, logger logging.Logger, cfg LifecycleConfig, hooks []ShutdownHook) {
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].Order < hooks[j].Order
+	})
+
+	for _, hook := range hooks {
+		l := logger.WithField("hook", hook.Name)
+		l.Debug("running shutdown hook")
+
+		start := time.Now()
+		err := hook.OnShutdown(ctx)
+		elapsed := time.Since(start)
+
+		l = l.WithField("runtime", elapsed)
+		if err != nil {
+			l.WithError(err).Error("shutdown hook failed")
+		} else if cfg.SlowHookThreshold > 0 && elapsed > cfg.SlowHookThreshold {
+			l.Warn("slow shutdown hook")
+		} else {
+			l.Debug("shutdown hook completed")
+		}
+	}
+}
+
+// warner is implemented by both logging.Logger and *logrus.Entry
+type warner interface {
+	Warnf(format string, args ...any)
+}
+
+// forceExitOnSignal ends the process immediately, with the exit code of ECANCELED, if another shutdown signal is
+// received before done is closed. The process ends even if the Runner reports exit codes using WithExit, as the app is
+// still stopping.
+func (r *Runner) forceExitOnSignal(logger warner, done <-chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		logger.Warnf("received second signal (%v), forcing exit", sig)
+		r.forceExit(r.ExitCode(errors.Canceledf("received second signal (%v)", sig)))
+
+	case <-done:
+	}
+}
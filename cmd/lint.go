@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/config"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.uber.org/fx"
+)
+
+// UnreadableFile denotes a dotenv file that could not be read or decrypted
+const UnreadableFile config.IssueKind = "unreadable"
+
+// LintIssue is an issue found in a dotenv file by `env lint`
+type LintIssue struct {
+	config.Issue
+	Environment string `json:"environment,omitempty"`
+	File        string `json:"file,omitempty"`
+}
+
+// lintCommand returns the `env lint` command, which validates dotenv files against the registered configs
+func (r *Runner) lintCommand(options ...fx.Option) *cobra.Command {
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "validate the dotenv files of every environment against the registered config",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+
+			dir, err := cmd.Flags().GetString("dir")
+			if err != nil {
+				return err
+			}
+
+			var cfgMgr config.Provider
+			if err = r.inspect(cmd, options, &cfgMgr); err != nil {
+				return err
+			}
+
+			validator, ok := cfgMgr.(config.Validator)
+			if !ok {
+				return errors.Configurationf("the config provider does not support validation")
+			}
+
+			issues, err := r.lint(cmd, validator, dir)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "json":
+				if issues == nil {
+					issues = []LintIssue{}
+				}
+
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err = enc.Encode(issues); err != nil {
+					return err
+				}
+
+			case "text":
+				for _, issue := range issues {
+					location := issue.File
+					if len(location) == 0 {
+						location = issue.Environment + " environment"
+					}
+
+					fmt.Fprintf(cmd.OutOrStdout(), "%s: %s %s (%s)\n", location, issue.Key, issue.Kind, issue.Message)
+				}
+
+			default:
+				return errors.Invalidf("unsupported format '%s'", format)
+			}
+
+			if len(issues) > 0 {
+				return errors.Invalidf("found %d issues in dotenv files", len(issues))
+			}
+
+			return nil
+		},
+	}
+	lintCmd.Flags().String("format", "text", "specifies the output format (text or json)")
+	lintCmd.Flags().String("dir", ".", "specifies the directory containing the dotenv files")
+
+	return lintCmd
+}
+
+func (r *Runner) lint(cmd *cobra.Command, cfgMgr config.Validator, dir string) ([]LintIssue, error) {
+	environments, err := dotenvEnvironments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variables read by the Runner itself rather than by a config
+	ignored := map[string]bool{
+		config.GetPrefixKey(r.prefix, "environment"): true,
+		config.GetPrefixKey(r.prefix, "loglevel"):    true,
+	}
+
+	var issues []LintIssue
+	seen := make(map[LintIssue]bool)
+	add := func(issue LintIssue) {
+		if issue.Kind == config.UnknownKey && ignored[issue.Key] {
+			return
+		}
+
+		if !seen[issue] {
+			seen[issue] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	for _, e := range environments {
+		name := e.String()
+		if e.IsLocal() {
+			name = "local"
+		}
+
+		merged := make(map[string]string)
+
+		for _, file := range e.Files() {
+			path := filepath.Join(dir, file)
+			if _, err = os.Stat(path); err != nil {
+				continue
+			}
+
+			vars, err := readDotenv(cmd, path)
+			if err != nil {
+				add(LintIssue{Issue: config.Issue{Kind: UnreadableFile, Message: err.Error()}, File: file})
+				continue
+			}
+
+			// Unknown keys and unparseable values are reported against the file that contains them
+			fileIssues, err := cfgMgr.Validate(cmd.Context(), vars)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, issue := range fileIssues {
+				if issue.Kind != config.MissingKey {
+					add(LintIssue{Issue: issue, File: file})
+				}
+			}
+
+			// Files earlier in the list take priority, as they do when loading
+			for k, v := range vars {
+				if _, ok := merged[k]; !ok {
+					merged[k] = v
+				}
+			}
+		}
+
+		// Missing keys are reported against the environment as a whole
+		envIssues, err := cfgMgr.Validate(cmd.Context(), merged)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range envIssues {
+			if issue.Kind == config.MissingKey {
+				add(LintIssue{Issue: issue, Environment: name})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// dotenvEnvironments returns every environment that has a dotenv file in the given directory
+func dotenvEnvironments(dir string) ([]env.Environment, error) {
+	files, err := filepath.Glob(filepath.Join(dir, ".env*"))
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[env.Environment]bool)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), env.EncryptedExtension)
+
+		switch {
+		case name == ".env.local":
+			found[env.Local()] = true
+		case strings.HasPrefix(name, ".env."):
+			found[env.Environment(strings.TrimPrefix(name, ".env."))] = true
+		}
+	}
+
+	if len(found) == 0 {
+		found[env.Local()] = true
+	}
+
+	var environments []env.Environment
+	for e := range found {
+		environments = append(environments, e)
+	}
+
+	sort.Slice(environments, func(i, j int) bool {
+		return environments[i] < environments[j]
+	})
+
+	return environments, nil
+}
+
+func readDotenv(cmd *cobra.Command, file string) (map[string]string, error) {
+	if !env.IsEncrypted(file) {
+		return env.ReadFile(file)
+	}
+
+	key, err := envKey(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.ReadEncryptedFile(key, file)
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"go.ketch.com/lib/orlop/v2/config"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module(
+	"cmd",
+	config.Option[LifecycleConfig]("lifecycle"),
+	fx.Provide(newCommands),
+	fx.Invoke(startScheduler),
+)
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/config"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.uber.org/fx"
+)
+
+// ReloadHook is notified after the environment and config have been reloaded following a SIGHUP. The configs injected
+// into the app are not modified by a reload, so the hook reads the reloaded values using config.Provider.
+type ReloadHook struct {
+	Name     string
+	OnReload func(ctx context.Context) error
+}
+
+// ReloadHookOption provides a ReloadHook from the given constructor
+func ReloadHookOption(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"reload"`),
+		),
+	)
+}
+
+// reloadOnSignal reloads the environment and config of each app each time a SIGHUP is received, until the returned
+// function is called
+func (r *Runner) reloadOnSignal(cmd *cobra.Command, params ...runParams) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				for _, p := range params {
+					p.Logger.Info("reloading")
+
+					if err := r.reload(cmd, p); err != nil {
+						p.Logger.WithError(err).Error("reload failed")
+					} else {
+						p.Logger.Info("reloaded")
+					}
+				}
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// reload re-reads the dotenv files, reloads the registered configs, the log level and the logging config and then runs
+// the reload hooks.
+// If the dotenv files or the configs cannot be loaded, the previous environment and configs are kept.
+func (r *Runner) reload(cmd *cobra.Command, p runParams) error {
+	ctx := cmd.Context()
+
+	envFlag, err := cmd.Flags().GetString("env")
+	if err != nil {
+		return err
+	}
+
+	configFiles, err := cmd.Flags().GetStringSlice("config")
+	if err != nil {
+		return err
+	}
+
+	e := env.Environment(envFlag)
+
+	restore, err := e.Reload(configFiles...)
+	if err != nil {
+		return err
+	}
+
+	reloader, ok := p.Config.(config.Reloader)
+	if !ok {
+		restore()
+		return errors.Configurationf("the config provider does not support reloading")
+	}
+
+	if err = reloader.Reload(ctx); err != nil {
+		restore()
+		return err
+	}
+
+	// An explicit --loglevel takes precedence over the environment, as it does at startup
+	loglevel := r.Getenv("loglevel")
+	if cmd.Flags().Changed("loglevel") {
+		if loglevel, err = cmd.Flags().GetString("loglevel"); err != nil {
+			return err
+		}
+	}
+	logging.SetupLogging(e, logging.Level(strings.ToLower(loglevel)))
+
+	if c, err := p.Config.Get(ctx, "logging"); err == nil {
+		if cfg, ok := c.(*logging.Config); ok {
+			logging.Configure(*cfg)
+		}
+	}
+
+	// The new config is in effect, so a failing hook is reported but does not undo the reload
+	var errs []error
+	for _, hook := range p.ReloadHooks {
+		if err = hook.OnReload(ctx); err != nil {
+			errs = append(errs, errors.Wrapf(err, "reload hook '%s' failed", hook.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
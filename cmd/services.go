@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.uber.org/fx"
+)
+
+// Service is a named service run alongside others in the same process. The Name is used as the service.Name, and so
+// as the prefix of the service's config.
+type Service struct {
+	Name    string
+	Options []fx.Option
+}
+
+type serviceApp struct {
+	name   string
+	app    *fx.App
+	params runParams
+}
+
+// SetupServices sets up the Command to run each of the services in the same process. The services are started in
+// order, and if any fails to start, those already started are stopped. On shutdown, they are stopped in reverse order.
+//
+// The init, check and docs commands cover every service. Each service has its own config and dependency graph, so the
+// env, graph and jobs commands are added under a command named after the service (e.g., `api graph`).
+func (r *Runner) SetupServices(cmd *cobra.Command, services ...Service) *Runner {
+	if cmd.RunE == nil {
+		cmd.RunE = r.runServicesE(services...)
+	}
+
+	cmd.AddCommand(r.servicesCommand("init", "output the config environment variables of every service and exits",
+		services, (*Runner).initCommand))
+	cmd.AddCommand(r.servicesCommand("check", "validate that every dependency of every service is provided and exits",
+		services, (*Runner).checkCommand))
+	cmd.AddCommand(r.versionCommand())
+	cmd.AddCommand(r.docsCommand(func(cmd *cobra.Command) ([]string, error) {
+		var vars []string
+		for _, svc := range services {
+			serviceVars, err := r.serviceRunner(svc).configVars(svc.Options...)(cmd)
+			if err != nil {
+				return nil, errors.Wrapf(err, "service %s", svc.Name)
+			}
+
+			vars = append(vars, serviceVars...)
+		}
+
+		return vars, nil
+	}))
+
+	for _, svc := range services {
+		sr := r.serviceRunner(svc)
+
+		serviceCmd := &cobra.Command{
+			Use:   svc.Name,
+			Short: "manage the " + svc.Name + " service",
+			Args:  cobra.NoArgs,
+		}
+		serviceCmd.AddCommand(sr.envCommand(svc.Options...))
+		serviceCmd.AddCommand(sr.graphCommand(svc.Options...))
+		serviceCmd.AddCommand(sr.jobsCommand(svc.Options...))
+		cmd.AddCommand(serviceCmd)
+	}
+
+	if !cmd.HasParent() {
+		cmd.InitDefaultCompletionCmd()
+	}
+
+	return r
+}
+
+// serviceRunner returns a copy of the Runner for the service, using the service name as the config prefix
+func (r *Runner) serviceRunner(svc Service) *Runner {
+	sr := *r
+	sr.prefix = svc.Name
+	return &sr
+}
+
+// servicesCommand returns a command running the command built by newCommand for each of the services in turn,
+// writing the name of each service as a comment before its output
+func (r *Runner) servicesCommand(use string, short string, services []Service, newCommand func(r *Runner, options ...fx.Option) *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, svc := range services {
+				fmt.Fprintf(cmd.OutOrStdout(), "# %s\n", svc.Name)
+
+				if err := newCommand(r.serviceRunner(svc), svc.Options...).RunE(cmd, args); err != nil {
+					return errors.Wrapf(err, "service %s", svc.Name)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func (r *Runner) runServicesE(services ...Service) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		loglevelFlag, err := cmd.Flags().GetString("loglevel")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := r.lifecycleConfig(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var apps []*serviceApp
+		for _, svc := range services {
+			sr := r.serviceRunner(svc)
+
+			s := &serviceApp{
+				name: svc.Name,
+			}
+
+			s.app = fx.New(
+				logging.WithLogger(log.New().WithField("service", svc.Name)),
+				fx.StartTimeout(cfg.StartTimeout),
+				fx.StopTimeout(cfg.StopTimeout),
+				sr.appOptions(cmd, logging.Level(loglevelFlag), svc.Options...),
+				fx.Decorate(func(l logging.Logger) logging.Logger {
+					return l.WithField("service", svc.Name)
+				}),
+				fx.Invoke(func(params runParams) { s.params = params }),
+			)
+
+			if err = s.app.Err(); err != nil {
+				return errors.Wrapf(err, "failed to create service %s", svc.Name)
+			}
+
+			apps = append(apps, s)
+		}
+
+		code, err := r.runServices(cmd, apps, cfg)
+		if err != nil {
+			r.exit(r.ExitCode(err))
+			return err
+		} else if code != 0 {
+			r.exit(code)
+			return exitError(code)
+		}
+
+		return nil
+	}
+}
+
+// runServices starts all the services, waits for a shutdown signal to any of them and then stops them all in reverse
+// order, returning the exit code requested by the signal. While running, a SIGHUP reloads every service.
+func (r *Runner) runServices(cmd *cobra.Command, services []*serviceApp, cfg LifecycleConfig) (int, error) {
+	startCtx, cancel := context.WithTimeout(context.Background(), cfg.StartTimeout)
+	defer cancel()
+
+	for n, s := range services {
+		if err := s.app.Start(startCtx); err != nil {
+			// Roll back the services that have already started
+			stopCtx, cancel := context.WithTimeout(context.Background(), cfg.StopTimeout)
+			defer cancel()
+
+			stopServices(stopCtx, services[:n], cfg)
+			return 1, errors.Wrapf(err, "failed to start service %s", s.name)
+		}
+
+		s.params.Logger.WithFields(s.params.Build.Fields()...).Info("started")
+	}
+
+	var params []runParams
+	for _, s := range services {
+		params = append(params, s.params)
+	}
+
+	stopReload := r.reloadOnSignal(cmd, params...)
+
+	// A shutdown signal to any of the services shuts them all down
+	signals := make(chan fx.ShutdownSignal, len(services))
+	for _, s := range services {
+		go func(app *fx.App) {
+			signals <- <-app.Wait()
+		}(s.app)
+	}
+
+	var sig fx.ShutdownSignal
+	select {
+	case sig = <-signals:
+	case <-cmd.Context().Done():
+	}
+	stopReload()
+
+	logger := log.New().WithField("signal", sig.Signal)
+	logger.Info("shutting down")
+
+	done := make(chan struct{})
+	defer close(done)
+
+	if cfg.ForceExit {
+		go r.forceExitOnSignal(logger, done)
+	}
+
+	if cfg.DrainDelay > 0 {
+		logger.WithField("delay", cfg.DrainDelay).Info("waiting for drain")
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), cfg.StopTimeout)
+	defer cancel()
+
+	if err := stopServices(stopCtx, services, cfg); err != nil {
+		return 1, err
+	}
+
+	logger.Info("shut down")
+
+	return sig.ExitCode, nil
+}
+
+// stopServices runs the shutdown hooks of each service and stops it, in reverse order, returning the first error
+func stopServices(ctx context.Context, services []*serviceApp, cfg LifecycleConfig) error {
+	var firstErr error
+
+	for n := len(services) - 1; n >= 0; n-- {
+		s := services[n]
+
+		runShutdownHooks(ctx, s.params.Logger, cfg, s.params.Hooks)
+
+		if err := s.app.Stop(ctx); err != nil {
+			s.params.Logger.WithError(err).Error("failed to stop")
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "failed to stop service %s", s.name)
+			}
+		}
+	}
+
+	return firstErr
+}
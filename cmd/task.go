@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.uber.org/fx"
+)
+
+// TaskFunc is the main function of a one-shot task
+type TaskFunc func(ctx context.Context) error
+
+var (
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	taskFuncType = reflect.TypeOf(TaskFunc(nil))
+)
+
+// Task returns an option that runs the app as a one-shot task rather than a long-lived service.
+//
+// The function must be of the form `func(ctx context.Context, deps...) error`. Its dependencies are injected by fx
+// and it is invoked once the app has started, after which the app is stopped and the process exits with the
+// function's error. The context is canceled if a shutdown signal (e.g., SIGINT or SIGTERM) is received.
+func Task(fn any) fx.Option {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() {
+		return fx.Error(errors.Internalf("cmd.Task requires a func(context.Context, ...) error, not nil"))
+	}
+
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() == 0 || t.In(0) != contextType || t.NumOut() != 1 || t.Out(0) != errorType {
+		return fx.Error(errors.Internalf("cmd.Task requires a func(context.Context, ...) error, not %v", t))
+	}
+	if v.IsNil() {
+		return fx.Error(errors.Internalf("cmd.Task requires a func(context.Context, ...) error, not a nil %v", t))
+	}
+
+	var in []reflect.Type
+	for n := 1; n < t.NumIn(); n++ {
+		in = append(in, t.In(n))
+	}
+
+	// Build a constructor taking the dependencies of fn and returning a TaskFunc closing over them
+	constructor := reflect.MakeFunc(reflect.FuncOf(in, []reflect.Type{taskFuncType}, false), func(args []reflect.Value) []reflect.Value {
+		task := TaskFunc(func(ctx context.Context) error {
+			out := v.Call(append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, args...))
+			if err, ok := out[0].Interface().(error); ok {
+				return err
+			}
+			return nil
+		})
+
+		return []reflect.Value{reflect.ValueOf(task)}
+	})
+
+	return fx.Provide(constructor.Interface())
+}
+
+// runTask runs the task until it completes or a shutdown signal is received, in which case the task is canceled and
+// given until the stop timeout to return
+func runTask(ctx context.Context, app *fx.App, p runParams) (fx.ShutdownSignal, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Task(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			p.Logger.WithError(err).Error("task failed")
+		} else {
+			p.Logger.Info("task completed")
+		}
+		return fx.ShutdownSignal{}, err
+
+	case sig := <-app.Wait():
+		p.Logger.WithField("signal", sig.Signal).Warn("canceling task")
+		cancel()
+
+		select {
+		case err := <-done:
+			if err == nil || errors.Is(err, context.Canceled) {
+				err = errors.Canceled(errors.Errorf("task canceled by %v", sig.Signal))
+			}
+			return sig, err
+
+		case <-time.After(app.StopTimeout()):
+			return sig, errors.Timeoutf("task did not stop within %v of being canceled", app.StopTimeout())
+		}
+	}
+}
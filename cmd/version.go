@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.ketch.com/lib/orlop/v2/errors"
+)
+
+// versionCommand returns the `version` command, which outputs the build metadata
+func (r *Runner) versionCommand() *cobra.Command {
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "output the version and build information and exits",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+
+			switch format {
+			case "json":
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(r.build)
+
+			case "text":
+				fmt.Fprintf(out, "version:    %s\n", r.build.Version)
+				if len(r.build.Revision) > 0 {
+					dirty := ""
+					if r.build.Dirty {
+						dirty = " (dirty)"
+					}
+					fmt.Fprintf(out, "revision:   %s%s\n", r.build.Revision, dirty)
+				}
+				if !r.build.BuildTime.IsZero() {
+					fmt.Fprintf(out, "build time: %s\n", r.build.BuildTime.Format(time.RFC3339))
+				}
+				_, err = fmt.Fprintf(out, "go version: %s\n", r.build.GoVersion)
+				return err
+
+			default:
+				return errors.Invalidf("unsupported format '%s'", format)
+			}
+		},
+	}
+	versionCmd.Flags().String("format", "text", "specifies the output format (text or json)")
+
+	return versionCmd
+}
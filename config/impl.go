@@ -26,6 +26,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 
 	"go.uber.org/fx"
 
@@ -45,6 +46,8 @@ type value struct {
 }
 
 type providerImpl struct {
+	// mu guards configs, which Reload replaces while the app is running
+	mu      sync.Mutex
 	configs map[string]value
 	environ env.Environ
 	prefix  service.Name
@@ -68,6 +71,10 @@ func New(p Params) Provider {
 
 func (s *providerImpl) Get(ctx context.Context, service string) (any, error) {
 	serviceName := strings.ToLower(service)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if cfg, ok := s.configs[serviceName]; ok {
 		if cfg.isPopulated {
 			return cfg.value, nil
@@ -78,6 +85,10 @@ func (s *providerImpl) Get(ctx context.Context, service string) (any, error) {
 			return cfg.value, err
 		}
 
+		// Once populated, the config is never written to again, so it can be read without holding the lock
+		cfg.isPopulated = true
+		s.configs[serviceName] = cfg
+
 		return cfg.value, nil
 	}
 
@@ -85,6 +96,9 @@ func (s *providerImpl) Get(ctx context.Context, service string) (any, error) {
 }
 
 func (s *providerImpl) List(_ context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var vars []string
 	for k, v := range s.configs {
 		vs, err := s.getVariablesFromConfig(k, v.value)
@@ -108,12 +122,7 @@ func (s *providerImpl) load(_ context.Context, key string, value any) error {
 
 	key = strings.TrimSpace(key)
 	for name, field := range fields {
-		keyName := name
-		if len(key) > 0 {
-			keyName = strings.Join([]string{key, name}, "_")
-		}
-
-		if v := s.environ.Getenv(keyName); len(v) > 0 {
+		if v := s.environ.Getenv(keyName(key, name)); len(v) > 0 {
 			if field.tag.NotSecret {
 				notSecretFields[name] = v
 			}
@@ -157,3 +157,87 @@ func Test_providerImpl_load(t *testing.T) {
 
 	assert.Equal(t, "string-data", data.String)
 }
+
+func Test_providerImpl_Reload(t *testing.T) {
+	var config TestConfig
+	ctx := context.Background()
+
+	p := New(Params{
+		Environ: env.NewEnviron("reload"),
+		Prefix:  "reload",
+		Defs: []Definition{
+			{
+				Name:   "config",
+				Config: &config,
+			},
+		},
+	})
+
+	get := func() *TestConfig {
+		c, err := p.Get(ctx, "config")
+		require.NoError(t, err)
+		return c.(*TestConfig)
+	}
+
+	t.Setenv("RELOAD_CONFIG_REQ", "before")
+	t.Setenv("RELOAD_CONFIG_PTR", "1")
+	before := get()
+
+	t.Setenv("RELOAD_CONFIG_REQ", "after")
+	require.NoError(t, p.(Reloader).Reload(ctx))
+	assert.Equal(t, "after", get().Required)
+
+	// The values returned before the reload are not modified
+	assert.Equal(t, "before", before.Required)
+	assert.Equal(t, "before", config.Required)
+
+	// A failed reload keeps the previous config
+	t.Setenv("RELOAD_CONFIG_REQ", "failed")
+	t.Setenv("RELOAD_CONFIG_PTR", "abc")
+	require.Error(t, p.(Reloader).Reload(ctx))
+	assert.Equal(t, "after", get().Required)
+	require.NotNil(t, get().Ptr)
+	assert.Equal(t, int32(1), *get().Ptr)
+}
+
+// Test_providerImpl_ReloadConcurrent reads the config while it is reloaded, for the race detector
+func Test_providerImpl_ReloadConcurrent(t *testing.T) {
+	ctx := context.Background()
+
+	p := New(Params{
+		Environ: env.NewEnviron("reload"),
+		Prefix:  "reload",
+		Defs: []Definition{
+			{
+				Name:   "config",
+				Config: &TestConfig{},
+			},
+		},
+	})
+
+	t.Setenv("RELOAD_CONFIG_REQ", "value")
+
+	c, err := p.Get(ctx, "config")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, p.(Reloader).Reload(ctx))
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		default:
+			assert.Equal(t, "value", c.(*TestConfig).Required)
+
+			c, err = p.Get(ctx, "config")
+			require.NoError(t, err)
+		}
+	}
+}
@@ -28,3 +28,14 @@ type Provider interface {
 	Get(ctx context.Context, service string) (any, error)
 	List(ctx context.Context) ([]string, error)
 }
+
+// Validator is implemented by providers that can check variables against the registered configs without loading them
+type Validator interface {
+	Validate(ctx context.Context, vars map[string]string) ([]Issue, error)
+}
+
+// Reloader is implemented by providers that can reload the registered configs. Reloaded configs are returned by
+// subsequent calls to Get, while the values returned before are left unchanged.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"reflect"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+)
+
+// Reload reloads every registered config from the environment. The configs are only updated if all of them load
+// successfully, otherwise the previous values are kept and the errors are returned together.
+//
+// The reloaded configs are published as new values, so the values previously returned by Get (and the configs
+// injected from them) are never modified and can be read without synchronization. Consumers that follow reloads call
+// Get again (e.g., in a reload hook).
+func (s *providerImpl) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	loaded := make(map[string]value)
+
+	for k, v := range s.configs {
+		cfg := reflect.New(reflect.TypeOf(v.value).Elem()).Interface()
+		if err := s.load(ctx, k, cfg); err != nil {
+			errs = append(errs, errors.Wrapf(err, "could not reload '%s' config", k))
+			continue
+		}
+
+		loaded[k] = value{
+			isPopulated: true,
+			value:       cfg,
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for k, v := range loaded {
+		s.configs[k] = v
+	}
+
+	return nil
+}
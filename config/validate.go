@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.ketch.com/lib/orlop/v2/env"
+)
+
+// IssueKind is the kind of problem found when validating variables
+type IssueKind string
+
+const (
+	// UnknownKey denotes a variable with the service prefix that no config reads
+	UnknownKey IssueKind = "unknown"
+
+	// MissingKey denotes a required variable that has no value and no default
+	MissingKey IssueKind = "missing"
+
+	// InvalidValue denotes a variable whose value cannot be parsed into its field
+	InvalidValue IssueKind = "invalid"
+)
+
+// Issue is a problem found when validating variables against the registered configs
+type Issue struct {
+	Key     string    `json:"key"`
+	Kind    IssueKind `json:"kind"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Validate checks the given variables against the registered configs using the same field setters as Get
+func (s *providerImpl) Validate(_ context.Context, vars map[string]string) ([]Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var issues []Issue
+	known := make(map[string]bool)
+
+	for k, v := range s.configs {
+		// Parse into a fresh copy so the populated configs are never touched
+		cfg := reflect.New(reflect.TypeOf(v.value).Elem()).Interface()
+
+		fields, err := reflectStruct([]string{}, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, field := range fields {
+			key := env.VariableName(s.prefix, keyName(strings.TrimSpace(k), name))
+			known[key] = true
+
+			if value := vars[key]; len(value) > 0 {
+				if err = field.set(field.v, value); err != nil {
+					issues = append(issues, Issue{Key: key, Kind: InvalidValue, Message: err.Error()})
+				}
+			} else if field.tag.DefaultValue == nil && field.tag.Required {
+				issues = append(issues, Issue{Key: key, Kind: MissingKey, Message: "required"})
+			}
+		}
+	}
+
+	prefix := env.VariableName(s.prefix, "")
+	for key := range vars {
+		if strings.HasPrefix(key, prefix) && !known[key] {
+			issues = append(issues, Issue{Key: key, Kind: UnknownKey, Message: "not used by any config"})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Key == issues[j].Key {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Key < issues[j].Key
+	})
+
+	return issues, nil
+}
+
+func keyName(key string, name string) string {
+	if len(key) > 0 {
+		return strings.Join([]string{key, name}, "_")
+	}
+
+	return name
+}
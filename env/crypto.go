@@ -0,0 +1,147 @@
+package env
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+)
+
+// EncryptedExtension is the extension of an encrypted dotenv file
+const EncryptedExtension = ".enc"
+
+// KeyEnvironmentKey is the environment variable holding the key used to decrypt dotenv files
+const KeyEnvironmentKey = "DOTENV_KEY"
+
+// KeyFileEnvironmentKey is the environment variable holding the path to a file containing the dotenv key
+const KeyFileEnvironmentKey = "DOTENV_KEY_FILE"
+
+// KeySize is the size of a dotenv key in bytes (AES-256)
+const KeySize = 32
+
+const encryptedPrefix = "orlop:v1:"
+
+// Key is a key used to encrypt and decrypt dotenv files
+type Key []byte
+
+// NewKey generates a new random Key
+func NewKey() (Key, error) {
+	k := make(Key, KeySize)
+	if _, err := rand.Read(k); err != nil {
+		return nil, errors.Wrap(err, "could not generate key")
+	}
+
+	return k, nil
+}
+
+// ParseKey parses a base64 or hex encoded Key
+func ParseKey(s string) (Key, error) {
+	s = strings.TrimSpace(s)
+
+	if k, err := base64.StdEncoding.DecodeString(s); err == nil && len(k) == KeySize {
+		return k, nil
+	}
+
+	if k, err := hex.DecodeString(s); err == nil && len(k) == KeySize {
+		return k, nil
+	}
+
+	return nil, errors.Configurationf("dotenv key must be a base64 or hex encoded %d byte key", KeySize)
+}
+
+// ReadKey reads the Key from the given file
+func ReadKey(file string) (Key, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(errors.Convert(err), "could not read dotenv key file '%s'", file)
+	}
+
+	return ParseKey(string(b))
+}
+
+// LoadKey returns the Key from KeyEnvironmentKey, or the file named in KeyFileEnvironmentKey
+func LoadKey() (Key, error) {
+	if s := os.Getenv(KeyEnvironmentKey); len(s) > 0 {
+		return ParseKey(s)
+	}
+
+	if file := os.Getenv(KeyFileEnvironmentKey); len(file) > 0 {
+		return ReadKey(file)
+	}
+
+	return nil, errors.Configurationf("%s or %s must be set to decrypt dotenv files", KeyEnvironmentKey, KeyFileEnvironmentKey)
+}
+
+// String returns the base64 encoding of the key
+func (k Key) String() string {
+	return base64.StdEncoding.EncodeToString(k)
+}
+
+// Encrypt encrypts the plaintext into the encrypted dotenv file format
+func (k Key) Encrypt(plaintext []byte) ([]byte, error) {
+	gcm, err := k.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "could not generate nonce")
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	return []byte(encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Decrypt decrypts data in the encrypted dotenv file format
+func (k Key) Decrypt(data []byte) ([]byte, error) {
+	gcm, err := k.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	s := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(s, encryptedPrefix) {
+		return nil, errors.Invalid(errors.New("not an encrypted dotenv file"))
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, encryptedPrefix))
+	if err != nil {
+		return nil, errors.Invalid(errors.Wrap(err, "could not decode encrypted dotenv file"))
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.Invalid(errors.New("encrypted dotenv file is truncated"))
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.Forbidden(errors.Wrap(err, "could not decrypt dotenv file"))
+	}
+
+	return plaintext, nil
+}
+
+func (k Key) cipher() (cipher.AEAD, error) {
+	if len(k) != KeySize {
+		return nil, errors.Configurationf("dotenv key must be %d bytes", KeySize)
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, errors.Configuration(err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// IsEncrypted returns true if the file name denotes an encrypted dotenv file
+func IsEncrypted(file string) bool {
+	return strings.HasSuffix(file, EncryptedExtension)
+}
@@ -0,0 +1,53 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_EncryptDecrypt(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	parsed, err := ParseKey(key.String())
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	data, err := key.Encrypt([]byte("SECRET=value\n"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "SECRET")
+
+	plaintext, err := key.Decrypt(data)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET=value\n", string(plaintext))
+
+	other, err := NewKey()
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(data)
+	require.Error(t, err)
+}
+
+func TestEnvironment_LoadEncrypted(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	data, err := key.Encrypt([]byte("ENV_TEST_ENCRYPTED=decrypted\n"))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".env.custom")
+	require.NoError(t, os.WriteFile(file+EncryptedExtension, data, 0o600))
+
+	t.Setenv(KeyEnvironmentKey, key.String())
+	t.Setenv("ENV_TEST_ENCRYPTED", "")
+	require.NoError(t, os.Unsetenv("ENV_TEST_ENCRYPTED"))
+
+	Test().Load(file)
+
+	assert.Equal(t, "decrypted", os.Getenv("ENV_TEST_ENCRYPTED"))
+}
@@ -21,8 +21,12 @@
 package env
 
 import (
-	"github.com/joho/godotenv"
 	"os"
+	"sync"
+
+	"github.com/joho/godotenv"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
 )
 
 // EnvironmentKey is the environment variable we look for to set the environment (prefixed with service name)
@@ -70,8 +74,23 @@ func (e Environment) String() string {
 	return string(e)
 }
 
-// Load loads the environment variables from the specified files and from the standard locations
+// Load loads the environment variables from the specified files and from the standard locations.
+//
+// Encrypted dotenv files (e.g., `.env.production.enc`) are decrypted in memory using the key from LoadKey.
 func (e Environment) Load(files ...string) {
+	for _, file := range e.Files(files...) {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+
+		if err := loadFile(file); err != nil {
+			log.WithError(err).WithField("file", file).Warn("could not load environment file")
+		}
+	}
+}
+
+// Files returns the dotenv files that are loaded for the environment, in priority order
+func (e Environment) Files(files ...string) []string {
 	var envFiles []string
 	envFiles = append(envFiles, files...)
 	if e.IsLocal() {
@@ -81,9 +100,159 @@ func (e Environment) Load(files ...string) {
 	}
 	envFiles = append(envFiles, ".env")
 
+	var out []string
 	for _, file := range envFiles {
-		if _, err := os.Stat(file); err == nil {
-			_ = godotenv.Load(file)
+		out = append(out, file)
+		if !IsEncrypted(file) {
+			out = append(out, file+EncryptedExtension)
+		}
+	}
+
+	return out
+}
+
+// ReadFile reads the variables from the given dotenv file, decrypting it if necessary
+func ReadFile(file string) (map[string]string, error) {
+	if !IsEncrypted(file) {
+		return godotenv.Read(file)
+	}
+
+	key, err := LoadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadEncryptedFile(key, file)
+}
+
+// ReadEncryptedFile reads the variables from the given encrypted dotenv file
+func ReadEncryptedFile(key Key, file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+
+	plaintext, err := key.Decrypt(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decrypt '%s'", file)
+	}
+
+	return godotenv.UnmarshalBytes(plaintext)
+}
+
+// loaded holds the variables set from dotenv files, which are the only ones Reload may change
+var (
+	loaded   = make(map[string]bool)
+	loadedMu sync.Mutex
+)
+
+func loadFile(file string) error {
+	vars, err := ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	// Like godotenv.Load, never override variables that are already set
+	for k, v := range vars {
+		if _, ok := os.LookupEnv(k); !ok {
+			if err = os.Setenv(k, v); err != nil {
+				return err
+			}
+			loaded[k] = true
 		}
 	}
+
+	return nil
+}
+
+// Reload re-reads the dotenv files loaded by Load, overriding the values previously loaded from them and removing
+// those no longer present. Variables that were set before the files were loaded are never changed. If any file cannot
+// be read, nothing is changed.
+//
+// The returned function restores the environment to its state before the reload.
+func (e Environment) Reload(files ...string) (restore func(), err error) {
+	vars := make(map[string]string)
+	var errs []error
+
+	for _, file := range e.Files(files...) {
+		if _, err = os.Stat(file); err != nil {
+			continue
+		}
+
+		fileVars, err := ReadFile(file)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "could not read '%s'", file))
+			continue
+		}
+
+		// Files earlier in the list take priority, as they do when loading
+		for k, v := range fileVars {
+			if _, ok := vars[k]; !ok {
+				vars[k] = v
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	previous := make(map[string]*string)
+	previousLoaded := make(map[string]bool)
+	for k := range loaded {
+		previousLoaded[k] = true
+	}
+
+	set := func(k string, v *string) {
+		if _, ok := previous[k]; !ok {
+			if current, ok := os.LookupEnv(k); ok {
+				previous[k] = &current
+			} else {
+				previous[k] = nil
+			}
+		}
+
+		if v != nil {
+			_ = os.Setenv(k, *v)
+		} else {
+			_ = os.Unsetenv(k)
+		}
+	}
+
+	for k, v := range vars {
+		if _, ok := os.LookupEnv(k); ok && !loaded[k] {
+			continue
+		}
+
+		set(k, &v)
+		loaded[k] = true
+	}
+
+	for k := range loaded {
+		if _, ok := vars[k]; !ok {
+			set(k, nil)
+			delete(loaded, k)
+		}
+	}
+
+	return func() {
+		loadedMu.Lock()
+		defer loadedMu.Unlock()
+
+		for k, v := range previous {
+			if v != nil {
+				_ = os.Setenv(k, *v)
+			} else {
+				_ = os.Unsetenv(k)
+			}
+		}
+
+		loaded = previousLoaded
+	}, nil
 }
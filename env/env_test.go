@@ -0,0 +1,44 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".env.reload")
+	require.NoError(t, os.WriteFile(file, []byte("ENV_TEST_RELOADED=before\nENV_TEST_REMOVED=here\nENV_TEST_PROCESS=file\n"), 0o600))
+
+	t.Setenv("ENV_TEST_PROCESS", "process")
+	t.Cleanup(func() {
+		os.Unsetenv("ENV_TEST_RELOADED")
+		os.Unsetenv("ENV_TEST_REMOVED")
+	})
+
+	Test().Load(file)
+	assert.Equal(t, "before", os.Getenv("ENV_TEST_RELOADED"))
+
+	require.NoError(t, os.WriteFile(file, []byte("ENV_TEST_RELOADED=after\nENV_TEST_PROCESS=file\n"), 0o600))
+
+	restore, err := Test().Reload(file)
+	require.NoError(t, err)
+	assert.Equal(t, "after", os.Getenv("ENV_TEST_RELOADED"))
+	assert.Equal(t, "process", os.Getenv("ENV_TEST_PROCESS"))
+	_, ok := os.LookupEnv("ENV_TEST_REMOVED")
+	assert.False(t, ok)
+
+	restore()
+	assert.Equal(t, "before", os.Getenv("ENV_TEST_RELOADED"))
+	assert.Equal(t, "here", os.Getenv("ENV_TEST_REMOVED"))
+
+	// An unreadable file leaves the environment unchanged
+	require.NoError(t, os.WriteFile(file+EncryptedExtension, []byte("not encrypted"), 0o600))
+	t.Setenv(KeyEnvironmentKey, "")
+	_, err = Test().Reload(file)
+	require.Error(t, err)
+	assert.Equal(t, "before", os.Getenv("ENV_TEST_RELOADED"))
+}
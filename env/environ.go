@@ -41,7 +41,12 @@ type environImpl struct {
 }
 
 func (e environImpl) Getenv(key string) string {
-	return os.Getenv(toScreamingDelimited(strings.Join([]string{string(e.prefix), key}, "_"), '_', 0, true))
+	return os.Getenv(VariableName(e.prefix, key))
+}
+
+// VariableName returns the name of the environment variable for the given key
+func VariableName(prefix service.Name, key string) string {
+	return toScreamingDelimited(strings.Join([]string{string(prefix), key}, "_"), '_', 0, true)
 }
 
 func toScreamingDelimited(s string, delimiter uint8, ignore uint8, screaming bool) string {
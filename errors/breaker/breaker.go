@@ -0,0 +1,305 @@
+// Package breaker stops calling failing dependencies, such as partner APIs, counting only the failures classified as
+// such by their error codes
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+// Default breaker settings
+const (
+	DefaultConsecutiveFailures = 5
+	DefaultOpenTimeout         = 30 * time.Second
+	DefaultHalfOpenRequests    = 1
+)
+
+// DefaultCodes are the codes of the errors counted as failures by default
+var DefaultCodes = []string{errors.EUNAVAILABLE, errors.ETIMEOUT}
+
+// State is the state of a breaker
+type State int
+
+const (
+	// Closed lets calls through, counting failures
+	Closed State = iota
+
+	// Open fails calls without making them, until the open timeout elapses
+	Open
+
+	// HalfOpen lets a limited number of trial calls through, closing the breaker if they succeed
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type options struct {
+	codes               map[string]bool
+	consecutiveFailures int
+	failureRate         float64
+	minRequests         int
+	window              time.Duration
+	openTimeout         time.Duration
+	halfOpenRequests    int
+	key                 func(ctx context.Context) string
+}
+
+// Option configures a Breaker or Group
+type Option func(o *options)
+
+// WithCodes sets the codes of the errors counted as failures (default DefaultCodes). Other errors, such as ENOTFOUND,
+// show that the dependency is responding, so they count as successes.
+func WithCodes(codes ...string) Option {
+	return func(o *options) {
+		o.codes = make(map[string]bool, len(codes))
+		for _, code := range codes {
+			o.codes[code] = true
+		}
+	}
+}
+
+// WithConsecutiveFailures sets the number of consecutive failures that opens the breaker. If zero, consecutive
+// failures do not open the breaker.
+func WithConsecutiveFailures(failures int) Option {
+	return func(o *options) {
+		o.consecutiveFailures = failures
+	}
+}
+
+// WithFailureRate opens the breaker when the fraction (between 0 and 1) of calls that failed within the window reaches
+// the rate, once at least minRequests calls were made in the window
+func WithFailureRate(rate float64, minRequests int, window time.Duration) Option {
+	return func(o *options) {
+		o.failureRate = min(max(rate, 0), 1)
+		o.minRequests = minRequests
+		o.window = window
+	}
+}
+
+// WithOpenTimeout sets how long the breaker stays open before letting trial calls through
+func WithOpenTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.openTimeout = d
+	}
+}
+
+// WithHalfOpenRequests sets the number of trial calls let through by a half-open breaker, all of which must succeed to
+// close it
+func WithHalfOpenRequests(requests int) Option {
+	return func(o *options) {
+		o.halfOpenRequests = max(requests, 1)
+	}
+}
+
+// WithKey sets the function returning the key of the breaker of a Group to use for a call (default
+// request.Integration)
+func WithKey(key func(ctx context.Context) string) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		consecutiveFailures: DefaultConsecutiveFailures,
+		openTimeout:         DefaultOpenTimeout,
+		halfOpenRequests:    DefaultHalfOpenRequests,
+		key: func(ctx context.Context) string {
+			return request.Integration(ctx)
+		},
+	}
+	WithCodes(DefaultCodes...)(&o)
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Breaker is a circuit breaker. While closed, it counts the calls that fail with one of its codes, and opens when the
+// consecutive failures or the failure rate reach their thresholds. While open, calls fail with EUNAVAILABLE without
+// being made. After the open timeout, the breaker is half-open and lets trial calls through, closing if they all
+// succeed and opening again if one fails.
+type Breaker struct {
+	name string
+	o    options
+	now  func() time.Time
+
+	mu                sync.Mutex
+	state             State
+	generation        uint64
+	consecutive       int
+	windowStart       time.Time
+	requests          int
+	failures          int
+	openedAt          time.Time
+	halfOpenInFlight  int
+	halfOpenSuccesses int
+}
+
+// New returns a new closed Breaker. The name identifies it in logs and errors.
+func New(name string, opts ...Option) *Breaker {
+	return newBreaker(name, newOptions(opts))
+}
+
+func newBreaker(name string, o options) *Breaker {
+	return &Breaker{
+		name: name,
+		o:    o,
+		now:  time.Now,
+	}
+}
+
+// Name returns the name of the breaker
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.o.openTimeout {
+		return HalfOpen
+	}
+
+	return b.state
+}
+
+// Do calls fn unless the breaker is open, and records whether it failed. If the breaker is open, or half-open with
+// all trial calls in flight, it returns an EUNAVAILABLE error without calling fn. Otherwise, it returns the error of fn.
+// If fn panics, the call is recorded as a failure and the panic continues.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	generation, err := b.allow(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			b.record(ctx, generation, true)
+			panic(r)
+		}
+
+		b.record(ctx, generation, err != nil && b.o.codes[errors.Code(err)])
+	}()
+
+	return fn(ctx)
+}
+
+// allow returns the generation of the state in which the call is let through, or an error if it is not
+func (b *Breaker) allow(ctx context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.o.openTimeout {
+		b.transition(ctx, HalfOpen)
+	}
+
+	switch b.state {
+	case Open:
+		return b.generation, errors.WithField(errors.Unavailablef("circuit breaker '%s' is open", b.name), "breaker",
+			b.name)
+
+	case HalfOpen:
+		if b.halfOpenInFlight >= b.o.halfOpenRequests {
+			return b.generation, errors.WithField(errors.Unavailablef("circuit breaker '%s' is half-open", b.name), "breaker",
+				b.name)
+		}
+		b.halfOpenInFlight++
+	}
+
+	return b.generation, nil
+}
+
+// record counts the outcome of a call let through in the given generation of the state. Calls that complete after the
+// breaker changed state, even if it has since returned to the same state, are ignored.
+func (b *Breaker) record(ctx context.Context, generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case Closed:
+		now := b.now()
+		if b.o.window > 0 && now.Sub(b.windowStart) >= b.o.window {
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+
+		b.requests++
+		if !failed {
+			b.consecutive = 0
+			return
+		}
+
+		b.consecutive++
+		b.failures++
+
+		if b.o.consecutiveFailures > 0 && b.consecutive >= b.o.consecutiveFailures ||
+			b.o.failureRate > 0 && b.requests >= b.o.minRequests &&
+				float64(b.failures) >= b.o.failureRate*float64(b.requests) {
+			b.transition(ctx, Open)
+		}
+
+	case HalfOpen:
+		b.halfOpenInFlight--
+		if failed {
+			b.transition(ctx, Open)
+			return
+		}
+
+		b.halfOpenSuccesses++
+		if b.halfOpenSuccesses >= b.o.halfOpenRequests {
+			b.transition(ctx, Closed)
+		}
+	}
+}
+
+// transition changes the state of the breaker, starting a new generation with reset counts. The lock must be held.
+func (b *Breaker) transition(ctx context.Context, state State) {
+	l := log.WithContext(ctx).
+		WithField("breaker", b.name).
+		WithField("from", b.state.String()).
+		WithField("to", state.String())
+
+	if state == Open {
+		l.Warn("circuit breaker opened")
+	} else {
+		l.Info("circuit breaker state changed")
+	}
+
+	b.state = state
+	b.generation++
+	b.consecutive = 0
+	b.windowStart = b.now()
+	b.requests = 0
+	b.failures = 0
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccesses = 0
+	if state == Open {
+		b.openedAt = b.windowStart
+	}
+}
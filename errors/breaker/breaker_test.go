@@ -0,0 +1,183 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+// clock is a manually advanced clock
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func fail(err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return err
+	}
+}
+
+func succeed(ctx context.Context) error {
+	return nil
+}
+
+func TestBreaker(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Now()}
+
+	b := New("partner", WithConsecutiveFailures(3), WithOpenTimeout(time.Minute), WithHalfOpenRequests(2))
+	b.now = c.now
+
+	// Errors with other codes do not count, and reset the consecutive failures
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	require.Error(t, b.Do(ctx, fail(errors.Timeoutf("slow"))))
+	require.Error(t, b.Do(ctx, fail(errors.NotFoundf("no such user"))))
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	assert.Equal(t, Closed, b.State())
+
+	err := b.Do(ctx, fail(errors.Unavailablef("down")))
+	assert.Equal(t, "down", err.Error())
+	assert.Equal(t, Open, b.State())
+
+	called := false
+	err = b.Do(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Equal(t, "partner", errors.Fields(err)["breaker"])
+
+	// After the open timeout, a failed trial opens the breaker again
+	c.t = c.t.Add(time.Minute)
+	assert.Equal(t, HalfOpen, b.State())
+	require.Error(t, b.Do(ctx, fail(errors.Timeoutf("slow"))))
+	assert.Equal(t, Open, b.State())
+
+	// All trials must succeed to close it
+	c.t = c.t.Add(time.Minute)
+	require.NoError(t, b.Do(ctx, succeed))
+	assert.Equal(t, HalfOpen, b.State())
+	require.NoError(t, b.Do(ctx, succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_HalfOpenLimit(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Now()}
+
+	b := New("partner", WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+	b.now = c.now
+
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	c.t = c.t.Add(time.Second)
+
+	// While the trial call is in flight, other calls are rejected
+	err := b.Do(ctx, func(ctx context.Context) error {
+		assert.True(t, errors.IsUnavailable(b.Do(ctx, succeed)))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_PanickingTrial(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Now()}
+
+	b := New("partner", WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+	b.now = c.now
+
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	c.t = c.t.Add(time.Second)
+
+	// A panicking trial call is a failure, and does not keep its slot
+	assert.Panics(t, func() {
+		_ = b.Do(ctx, func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, Open, b.State())
+
+	c.t = c.t.Add(time.Second)
+	require.NoError(t, b.Do(ctx, succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_StaleTrial(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Now()}
+
+	b := New("partner", WithConsecutiveFailures(1), WithOpenTimeout(time.Second), WithHalfOpenRequests(2))
+	b.now = c.now
+
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	c.t = c.t.Add(time.Second)
+
+	err := b.Do(ctx, func(ctx context.Context) error {
+		// Another trial fails, so the breaker opens and then becomes half-open again while this one is in flight
+		require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+		c.t = c.t.Add(time.Second)
+		require.NoError(t, b.Do(ctx, succeed))
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The stale trial is not counted, so a second trial of the new half-open state is needed to close the breaker
+	assert.Equal(t, HalfOpen, b.State())
+	require.NoError(t, b.Do(ctx, succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_FailureRate(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Now()}
+
+	b := New("partner", WithConsecutiveFailures(0), WithFailureRate(0.5, 4, time.Minute),
+		WithCodes(errors.EUNAVAILABLE, errors.ERATELIMITED))
+	b.now = c.now
+
+	require.Error(t, b.Do(ctx, fail(errors.RateLimitedf("slow down"))))
+	require.NoError(t, b.Do(ctx, succeed))
+	require.Error(t, b.Do(ctx, fail(errors.Timeoutf("slow"))))
+	assert.Equal(t, Closed, b.State(), "fewer than the minimum requests")
+
+	// A new window starts the counts again
+	c.t = c.t.Add(time.Minute)
+	require.NoError(t, b.Do(ctx, succeed))
+	require.NoError(t, b.Do(ctx, succeed))
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	assert.Equal(t, Closed, b.State())
+
+	require.Error(t, b.Do(ctx, fail(errors.Unavailablef("down"))))
+	assert.Equal(t, Open, b.State())
+}
+
+func TestGroup(t *testing.T) {
+	g := NewGroup("partners", WithConsecutiveFailures(1))
+	acme := request.WithIntegration(context.Background(), "acme")
+	globex := request.WithIntegration(context.Background(), "globex")
+
+	require.NoError(t, g.Check())
+
+	require.Error(t, g.Do(acme, fail(errors.Unavailablef("down"))))
+	require.NoError(t, g.Do(globex, succeed))
+
+	assert.True(t, errors.IsUnavailable(g.Do(acme, succeed)))
+	assert.Equal(t, map[string]State{"acme": Open, "globex": Closed}, g.States())
+	assert.Equal(t, "partners/acme", g.Breaker("acme").Name())
+
+	err := g.Check()
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Contains(t, err.Error(), "acme")
+}
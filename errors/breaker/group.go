@@ -0,0 +1,82 @@
+package breaker
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+)
+
+// Group is a set of breakers with the same settings, one per key (e.g., per integration), so a failing partner does
+// not stop calls to the others
+type Group struct {
+	name string
+	o    options
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewGroup returns a new Group. The breaker for a call is chosen by the key function (see WithKey).
+func NewGroup(name string, opts ...Option) *Group {
+	return &Group{
+		name:     name,
+		o:        newOptions(opts),
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Breaker returns the breaker for the key, creating it if needed. Its name is the name of the group and the key
+// (e.g., `partners/acme`).
+func (g *Group) Breaker(key string) *Breaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, ok := g.breakers[key]
+	if !ok {
+		b = newBreaker(g.name+"/"+key, g.o)
+		g.breakers[key] = b
+	}
+
+	return b
+}
+
+// Do calls fn through the breaker for the key of ctx, as Breaker.Do
+func (g *Group) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return g.Breaker(g.o.key(ctx)).Do(ctx, fn)
+}
+
+// States returns the current state of each breaker by key, for health reporting
+func (g *Group) States() map[string]State {
+	g.mu.Lock()
+	breakers := maps.Clone(g.breakers)
+	g.mu.Unlock()
+
+	states := make(map[string]State, len(breakers))
+	for key, b := range breakers {
+		states[key] = b.State()
+	}
+
+	return states
+}
+
+// Check returns an EUNAVAILABLE error listing the keys of the open breakers, for health reporting. If no breaker is
+// open, it returns nil.
+func (g *Group) Check() error {
+	var open []string
+	for key, state := range g.States() {
+		if state == Open {
+			open = append(open, key)
+		}
+	}
+
+	if len(open) == 0 {
+		return nil
+	}
+
+	slices.Sort(open)
+	return errors.Unavailablef("circuit breakers of '%s' open for %s", g.name, strings.Join(open, ", "))
+}
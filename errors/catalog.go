@@ -0,0 +1,177 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+// catalogs holds the message templates by locale and then by error code or message ID
+var catalogs = struct {
+	sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]*template.Template
+}{
+	defaultLocale: "en",
+	messages:      make(map[string]map[string]*template.Template),
+}
+
+// SetDefaultLocale sets the locale used when none of the requested locales has a message (default `en`)
+func SetDefaultLocale(locale string) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	catalogs.defaultLocale = normalizeLocale(locale)
+}
+
+// DefaultLocale returns the locale used when none of the requested locales has a message
+func DefaultLocale() string {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	return catalogs.defaultLocale
+}
+
+// RegisterMessages adds messages for the locale (e.g., `fr` or `fr-CA`), keyed by error code (e.g., `not_found`) or by
+// message ID (see WithUserMessageID). Messages are text/template templates, executed with the arguments of the
+// message ID (e.g., `{{.name}} est requis`).
+func RegisterMessages(locale string, messages map[string]string) error {
+	parsed := make(map[string]*template.Template, len(messages))
+	for key, msg := range messages {
+		tmpl, err := template.New(key).Option("missingkey=zero").Parse(msg)
+		if err != nil {
+			return Configuration(Wrapf(err, "invalid message '%s' for locale '%s'", key, locale))
+		}
+
+		parsed[key] = tmpl
+	}
+
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	locale = normalizeLocale(locale)
+	if catalogs.messages[locale] == nil {
+		catalogs.messages[locale] = make(map[string]*template.Template)
+	}
+	for key, tmpl := range parsed {
+		catalogs.messages[locale][key] = tmpl
+	}
+
+	return nil
+}
+
+// LoadMessages registers the messages of each JSON file in the directory of fsys (usually an embed.FS), which is
+// named for its locale (e.g., `messages/fr-CA.json`) and holds an object of messages keyed by code or message ID.
+func LoadMessages(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return Configuration(err)
+	}
+
+	for _, file := range files {
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return Configuration(Wrapf(err, "could not read '%s'", file))
+		}
+
+		var messages map[string]string
+		if err = json.Unmarshal(b, &messages); err != nil {
+			return Configuration(Wrapf(err, "could not parse '%s'", file))
+		}
+
+		if err = RegisterMessages(strings.TrimSuffix(path.Base(file), ".json"), messages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LocalizedUserMessage returns the user message of err in the first of the locales (e.g., from request.Locales) that
+// has one, or else in the default locale, together with the locale of the message:
+//   - a message ID (see WithUserMessageID) is looked up in the catalogs, falling back to the standard message of the
+//     error code if no locale has it
+//   - a message set by WithUserMessage is returned as is
+//   - otherwise, the error code is looked up in the catalogs, falling back to UserMessage, so the original message of
+//     a user-facing error is kept (the details of other errors are hidden by the sanitize policy)
+//
+// The messages of aggregated errors are localized individually.
+// If err is nil, it returns empty strings.
+func LocalizedUserMessage(err error, locales ...string) (string, string) {
+	if err == nil {
+		return "", ""
+	}
+
+	if errs := Errors(err); len(errs) > 1 {
+		var msgs []string
+		var locale string
+		for n, e := range errs {
+			msg, l := LocalizedUserMessage(e, locales...)
+			if n == 0 {
+				locale = l
+			}
+			msgs = append(msgs, msg)
+		}
+
+		return strings.Join(msgs, "; "), locale
+	}
+
+	var um internal.UserMessage
+	if As(err, &um) {
+		if mid, ok := um.(internal.UserMessageID); ok {
+			id, args := mid.UserMessageID()
+			if msg, locale, ok := localize(id, args, locales); ok {
+				return msg, locale
+			}
+
+			return internal.StandardToMessage[Code(err)], DefaultLocale()
+		}
+
+		return um.UserMessage(), DefaultLocale()
+	}
+
+	if msg, locale, ok := localize(Code(err), nil, locales); ok {
+		return msg, locale
+	}
+
+	return UserMessage(err), DefaultLocale()
+}
+
+// localize returns the message for the key in the first of the locales, or of their base languages, that has it, or
+// else in the default locale
+func localize(key string, args map[string]any, locales []string) (string, string, bool) {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	candidates := make([]string, 0, 2*len(locales)+1)
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		candidates = append(candidates, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			candidates = append(candidates, base)
+		}
+	}
+	candidates = append(candidates, catalogs.defaultLocale)
+
+	for _, locale := range candidates {
+		if tmpl, ok := catalogs.messages[locale][key]; ok {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, args); err == nil {
+				return buf.String(), locale, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// normalizeLocale returns the locale in lowercase with hyphens (e.g., `fr_CA` becomes `fr-ca`)
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"embed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/messages/*.json
+var testMessages embed.FS
+
+func TestLocalizedUserMessage(t *testing.T) {
+	require.NoError(t, LoadMessages(testMessages, "testdata/messages"))
+
+	required := WithUserMessageID(Invalidf("name missing"), "test.required", map[string]any{"name": "Name"})
+
+	for _, tt := range []struct {
+		name    string
+		err     error
+		locales []string
+		msg     string
+		locale  string
+	}{
+		{"nil", nil, nil, "", ""},
+		{"id in default locale", required, nil, "Name is required.", "en"},
+		{"id in locale", required, []string{"fr-CA"}, "Le champ Name est obligatoire.", "fr-ca"},
+		{"id in base language", required, []string{"fr-FR"}, "Name est requis.", "fr"},
+		{"id in first locale with it", required, []string{"de", "fr"}, "Name est requis.", "fr"},
+		{"id in no locale", WithUserMessageID(Conflictf("taken"), "test.missing", nil), []string{"fr"},
+			"conflict", "en"},
+		{"code", NotFoundf("no such user"), []string{"fr_CA"}, "L'élément demandé est introuvable.", "fr"},
+		{"code in no locale", Conflictf("taken"), []string{"fr"}, "taken", "en"},
+		{"explicit message", WithUserMessage(NotFoundf("no such user"), "No such user"), []string{"fr"}, "No such user",
+			"en"},
+		{"aggregate", Join(WithParameter(required, "name"), NotFoundf("no such user")), []string{"fr"},
+			"Name est requis.; L'élément demandé est introuvable.", "fr"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, locale := LocalizedUserMessage(tt.err, tt.locales...)
+			assert.Equal(t, tt.msg, msg)
+			assert.Equal(t, tt.locale, locale)
+		})
+	}
+
+	assert.Equal(t, "Name is required.", UserMessage(required))
+}
+
+func TestRegisterMessages_InvalidTemplate(t *testing.T) {
+	err := RegisterMessages("en", map[string]string{"test.broken": "{{.name"})
+	assert.Equal(t, ECONFIGURATION, Code(err))
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+// Converters for errors of the standard library, which Convert otherwise treats as EINTERNAL. They are opt-in, so
+// register the sets that suit the service (e.g., `errors.RegisterConverter(errors.SQLConverters...)`). Errors that
+// already have a code are left to the built-in converters, and the original error is preserved in the chain.
+var (
+	// ContextConverters converts context.Canceled to ECANCELED and context.DeadlineExceeded to ETIMEOUT
+	ContextConverters = []Converter{convertContextError}
+
+	// NetworkConverters converts net and net/url errors to ETIMEOUT if they timed out, or else to EUNAVAILABLE
+	NetworkConverters = []Converter{convertNetworkError}
+
+	// SQLConverters converts sql.ErrNoRows to ENOTFOUND, sql.ErrTxDone to ECONFLICT and sql.ErrConnDone to EUNAVAILABLE
+	SQLConverters = []Converter{convertSQLError}
+
+	// DecodingConverters converts JSON syntax and type errors, strconv errors and io.ErrUnexpectedEOF (e.g., from a
+	// truncated request body) to EINVALID, with the JSON field as the parameter
+	DecodingConverters = []Converter{convertDecodingError}
+
+	// StandardLibraryConverters is all the above, in the order to register them: RegisterConverter gives precedence to
+	// the converters registered last, so the context errors within network errors are converted first
+	StandardLibraryConverters = slices.Concat(DecodingConverters, SQLConverters, NetworkConverters, ContextConverters)
+)
+
+// hasCode returns true if err's error chain already has an error code
+func hasCode(err error) bool {
+	var ec internal.ErrorCode
+	return As(err, &ec)
+}
+
+func convertContextError(err error) (error, bool) {
+	switch {
+	case hasCode(err):
+		return nil, false
+
+	case Is(err, context.Canceled):
+		return Canceled(err), true
+
+	case Is(err, context.DeadlineExceeded):
+		return Timeout(err), true
+
+	default:
+		return nil, false
+	}
+}
+
+func convertNetworkError(err error) (error, bool) {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var urlErr *url.Error
+
+	// A network call interrupted by its context is canceled or timed out as the context, not unavailable
+	if newErr, ok := convertContextError(err); ok {
+		return newErr, true
+	}
+
+	switch {
+	case hasCode(err):
+		return nil, false
+
+	case As(err, &dnsErr):
+		if dnsErr.Timeout() {
+			return Timeout(err), true
+		}
+		return Unavailable(err), true
+
+	case As(err, &opErr):
+		if opErr.Timeout() {
+			return Timeout(err), true
+		}
+		return Unavailable(err), true
+
+	case As(err, &urlErr):
+		if urlErr.Timeout() {
+			return Timeout(err), true
+		}
+		return Unavailable(err), true
+
+	default:
+		return nil, false
+	}
+}
+
+func convertSQLError(err error) (error, bool) {
+	switch {
+	case hasCode(err):
+		return nil, false
+
+	case Is(err, sql.ErrNoRows):
+		return NotFound(err), true
+
+	case Is(err, sql.ErrTxDone):
+		return Conflict(err), true
+
+	case Is(err, sql.ErrConnDone):
+		return Unavailable(err), true
+
+	default:
+		return nil, false
+	}
+}
+
+func convertDecodingError(err error) (error, bool) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+
+	switch {
+	case hasCode(err):
+		return nil, false
+
+	case As(err, &typeErr):
+		if len(typeErr.Field) > 0 {
+			return WithParameter(Invalid(err), typeErr.Field), true
+		}
+		return Invalid(err), true
+
+	case As(err, &syntaxErr), As(err, &numErr), Is(err, io.ErrUnexpectedEOF):
+		return Invalid(err), true
+
+	default:
+		return nil, false
+	}
+}
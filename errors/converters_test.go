@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+func TestStandardLibraryConverters(t *testing.T) {
+	// The converters are registered as a service would, restoring the built-in ones afterwards
+	converters := internal.Converters
+	t.Cleanup(func() {
+		internal.Converters = converters
+	})
+	RegisterConverter(StandardLibraryConverters...)
+
+	var typeErr error = json.Unmarshal([]byte(`{"age":"ten"}`), &struct {
+		Age int `json:"age"`
+	}{})
+	var syntaxErr error = json.Unmarshal([]byte(`{`), &struct{}{})
+	_, numErr := strconv.Atoi("ten")
+
+	timeoutErr := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	refusedErr := &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+
+	for _, tt := range []struct {
+		name  string
+		err   error
+		code  string
+		param string
+	}{
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), ECANCELED, ""},
+		{"deadline exceeded", context.DeadlineExceeded, ETIMEOUT, ""},
+		{"dns", &net.DNSError{Err: "no such host", Name: "partner.example", IsNotFound: true}, EUNAVAILABLE, ""},
+		{"dns timeout", &net.DNSError{Err: "timeout", Name: "partner.example", IsTimeout: true}, ETIMEOUT, ""},
+		{"net", refusedErr, EUNAVAILABLE, ""},
+		{"net timeout", timeoutErr, ETIMEOUT, ""},
+		{"url", &url.Error{Op: "Get", URL: "https://partner.example", Err: refusedErr}, EUNAVAILABLE, ""},
+		{"url timeout", &url.Error{Op: "Get", URL: "https://partner.example", Err: timeoutErr}, ETIMEOUT, ""},
+		{"no rows", fmt.Errorf("get user: %w", sql.ErrNoRows), ENOTFOUND, ""},
+		{"tx done", sql.ErrTxDone, ECONFLICT, ""},
+		{"conn done", sql.ErrConnDone, EUNAVAILABLE, ""},
+		{"json type", typeErr, EINVALID, "age"},
+		{"json syntax", syntaxErr, EINVALID, ""},
+		{"strconv", numErr, EINVALID, ""},
+		{"already coded", Conflict(sql.ErrNoRows), ECONFLICT, ""},
+		{"url canceled", &url.Error{Op: "Get", URL: "https://partner.example", Err: context.Canceled}, ECANCELED, ""},
+		{"net deadline exceeded", &net.OpError{Op: "dial", Net: "tcp", Err: context.DeadlineExceeded}, ETIMEOUT, ""},
+		{"unexpected eof", fmt.Errorf("decode body: %w", io.ErrUnexpectedEOF), EINVALID, ""},
+		{"unknown", io.ErrClosedPipe, EINTERNAL, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			converted := Convert(tt.err)
+
+			assert.Equal(t, tt.code, Code(converted))
+			assert.Equal(t, tt.param, Parameter(converted))
+			assert.ErrorIs(t, converted, tt.err)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package errors
+
+import (
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+// ExitCode returns the process exit code associated with an error. Errors in the chain providing an ExitCode method
+// (e.g., *exec.ExitError) use that code, and others follow the BSD sysexits.h conventions (e.g., 78 for ECONFIGURATION,
+// 69 for EUNAVAILABLE and 64 for EINVALID).
+// If err is nil, it returns 0.
+func ExitCode(err error) int {
+	var ec interface{ ExitCode() int }
+	if As(err, &ec) && ec.ExitCode() > 0 {
+		return ec.ExitCode()
+	}
+
+	if code, ok := internal.StandardToExit[Code(err)]; ok {
+		return code
+	}
+
+	return internal.StandardToExit["default"]
+}
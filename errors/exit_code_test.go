@@ -0,0 +1,24 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testExitCoder int
+
+func (e testExitCoder) Error() string {
+	return "exit"
+}
+
+func (e testExitCoder) ExitCode() int {
+	return int(e)
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 64, ExitCode(Invalidf("invalid")))
+	assert.Equal(t, 3, ExitCode(Wrap(testExitCoder(3), "failed")))
+	assert.Equal(t, 70, ExitCode(testExitCoder(0)))
+}
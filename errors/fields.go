@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+type fielder struct {
+	error
+	fields map[string]any
+}
+
+func (f fielder) Cause() error {
+	return f.error
+}
+
+func (f fielder) Unwrap() error {
+	return f.error
+}
+
+func (f fielder) Fields() map[string]any {
+	return f.fields
+}
+
+// WithField adds a field with the given key and value to err's error chain, for debugging (e.g., an ID or count).
+// Unlike pkg/errors, WithField will wrap nil error.
+func WithField(err error, key string, value any) error {
+	return WithFields(err, map[string]any{key: value})
+}
+
+// WithFields adds the fields to err's error chain.
+// Unlike pkg/errors, WithFields will wrap nil error.
+func WithFields(err error, fields map[string]any) error {
+	if err == nil {
+		err = New("Fields")
+	}
+
+	f := make(map[string]any, len(fields))
+	for k, v := range fields {
+		f[k] = v
+	}
+
+	return fielder{err, f}
+}
+
+// Fields returns the fields added anywhere in err's error chain, including to aggregated errors. Where the same key
+// was added more than once, the outermost value is returned.
+// If err is nil or has no fields, it returns nil.
+func Fields(err error) map[string]any {
+	fields := make(map[string]any)
+	collectFields(err, fields)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fields
+}
+
+func collectFields(err error, fields map[string]any) {
+	if err == nil {
+		return
+	}
+
+	if f, ok := err.(internal.Fields); ok {
+		for k, v := range f.Fields() {
+			if _, exists := fields[k]; !exists {
+				fields[k] = v
+			}
+		}
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		collectFields(u.Unwrap(), fields)
+
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			collectFields(e, fields)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFields(t *testing.T) {
+	assert.Nil(t, Fields(nil))
+	assert.Nil(t, Fields(New("no fields")))
+
+	err := WithField(NotFoundf("no such user"), "userId", "u-1")
+	err = Wrap(WithFields(err, map[string]any{"tenant": "acme", "userId": "u-2"}), "lookup failed")
+	err = WithField(err, "attempts", 3)
+
+	assert.Equal(t, map[string]any{
+		"userId":   "u-2",
+		"tenant":   "acme",
+		"attempts": 3,
+	}, Fields(err))
+	assert.Equal(t, ENOTFOUND, Code(err))
+	assert.Equal(t, "lookup failed: no such user", err.Error())
+
+	joined := Join(err, WithField(Invalidf("bad input"), "field", "name"))
+	assert.Equal(t, map[string]any{
+		"userId":   "u-2",
+		"tenant":   "acme",
+		"attempts": 3,
+		"field":    "name",
+	}, Fields(joined))
+
+	// The fields passed are copied
+	fields := map[string]any{"k": "v"}
+	err = WithFields(New("copied"), fields)
+	fields["k"] = "changed"
+	assert.Equal(t, map[string]any{"k": "v"}, Fields(err))
+}
@@ -0,0 +1,110 @@
+package grpcerrors
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"go.ketch.com/lib/orlop/v2/errors/sanitize"
+	"go.ketch.com/lib/orlop/v2/request"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor that converts errors returned by handlers to a status with details,
+// as Status, sanitized by the policy and localized for the caller (see ServerStatus)
+func UnaryServerInterceptor(policy *sanitize.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ServerStatus(ctx, policy, err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that converts errors returned by stream handlers to a status with
+// details, as Status, sanitized by the policy and localized for the caller (see ServerStatus)
+func StreamServerInterceptor(policy *sanitize.Policy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ServerStatus(ss.Context(), policy, err).Err()
+		}
+
+		return nil
+	}
+}
+
+// ServerStatus returns the status a server returns to clients for err, as Status. The error is sanitized by the
+// policy, localized for the locales of the request in ctx and given a RequestInfo with the correlation ID of the
+// request. Request fields missing from ctx are taken from the incoming metadata of the call (e.g., `request-id` and
+// `accept-language`).
+func ServerStatus(ctx context.Context, policy *sanitize.Policy, err error) *status.Status {
+	for _, key := range request.AllKeys {
+		if len(request.Getters[key](ctx)) == 0 {
+			if values := metadata.ValueFromIncomingContext(ctx, request.KeyMap[key]); len(values) > 0 {
+				ctx = request.Setters[key](ctx, strings.Join(values, ","))
+			}
+		}
+	}
+
+	s := Status(policy.Sanitize(ctx, err), request.Locales(ctx)...)
+
+	if id := sanitize.CorrelationID(ctx); len(id) > 0 {
+		if ds, detailsErr := s.WithDetails(&errdetails.RequestInfo{RequestId: id}); detailsErr == nil {
+			return ds
+		}
+	}
+
+	return s
+}
+
+// UnaryClientInterceptor returns an interceptor that converts errors returned by calls to orlop errors, as FromError
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		return FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns an interceptor that converts errors returned by streams to orlop errors, as
+// FromError
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer,
+		opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromError(err)
+		}
+
+		return &clientStream{cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return convertStreamError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return convertStreamError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return convertStreamError(s.ClientStream.CloseSend())
+}
+
+// convertStreamError converts err as FromError, except for io.EOF, which signals the end of the stream
+func convertStreamError(err error) error {
+	if err == io.EOF {
+		return err
+	}
+
+	return FromError(err)
+}
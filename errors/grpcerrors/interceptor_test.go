@@ -0,0 +1,215 @@
+package grpcerrors
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/errors/sanitize"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer fails every call with err
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	err error
+}
+
+func (s *healthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, s.err
+}
+
+func (s *healthServer) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	return s.err
+}
+
+func newClient(t *testing.T, srv *healthServer, policy *sanitize.Policy) grpc_health_v1.HealthClient {
+	lis := bufconn.Listen(1024 * 1024)
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(policy)),
+		grpc.StreamInterceptor(StreamServerInterceptor(policy)),
+	)
+	grpc_health_v1.RegisterHealthServer(s, srv)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestInterceptors(t *testing.T) {
+	name := errors.WithUserMessage(errors.WithParameter(errors.Invalidf("name is empty"), "name"), "name is required")
+	age := errors.WithUserMessage(errors.WithParameter(errors.Invalidf("age is negative"), "age"), "age must be positive")
+
+	srv := &healthServer{}
+	client := newClient(t, srv, sanitize.NewPolicy(env.Test()))
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"unary": func() error {
+			_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			return err
+		},
+		"stream": func() error {
+			stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+	}
+
+	for callName, call := range calls {
+		t.Run(callName, func(t *testing.T) {
+			t.Run("single", func(t *testing.T) {
+				srv.err = errors.WithSource(name, "validator")
+
+				err := call()
+				require.Error(t, err)
+				assert.Equal(t, errors.EINVALID, errors.Code(err))
+				assert.Equal(t, "name", errors.Parameter(err))
+				assert.Equal(t, "name is required", errors.UserMessage(err))
+				assert.Equal(t, "validator", errors.Source(err))
+			})
+
+			t.Run("aggregate", func(t *testing.T) {
+				srv.err = errors.Join(name, age)
+
+				err := call()
+				require.Error(t, err)
+				assert.Equal(t, errors.EINVALID, errors.Code(err))
+				assert.Equal(t, []string{"name", "age"}, errors.Parameters(err))
+				assert.Equal(t, "name is required; age must be positive", errors.UserMessage(err))
+
+				errs := errors.Errors(err)
+				require.Len(t, errs, 2)
+				assert.Equal(t, "name", errors.Parameter(errs[0]))
+				assert.Equal(t, "name is required", errors.UserMessage(errs[0]))
+				assert.Equal(t, "age", errors.Parameter(errs[1]))
+				assert.Equal(t, "age must be positive", errors.UserMessage(errs[1]))
+			})
+
+			t.Run("code without grpc mapping", func(t *testing.T) {
+				srv.err = errors.Configurationf("missing key")
+
+				err := call()
+				require.Error(t, err)
+				assert.Equal(t, errors.ECONFIGURATION, errors.Code(err))
+				assert.Equal(t, codes.Internal, status.Code(err))
+				assert.Empty(t, errors.Parameter(err))
+			})
+
+			t.Run("uncoded", func(t *testing.T) {
+				srv.err = errors.New("boom")
+
+				err := call()
+				require.Error(t, err)
+				assert.Equal(t, errors.EINTERNAL, errors.Code(err))
+				assert.Equal(t, "boom", status.Convert(err).Message())
+			})
+		})
+	}
+}
+
+func TestInterceptors_Sanitized(t *testing.T) {
+	srv := &healthServer{}
+	client := newClient(t, srv, sanitize.NewPolicy(env.Production()))
+	ctx := context.Background()
+
+	srv.err = errors.WithParameter(errors.Internalf("connecting to db-7: password rejected"), "db")
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.Error(t, err)
+	assert.Equal(t, errors.EINTERNAL, errors.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+	assert.Empty(t, errors.Parameter(err))
+
+	// The correlation ID is taken from the incoming metadata
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs("request-id", "req-1"))
+	s := ServerStatus(incoming, sanitize.NewPolicy(env.Production()), srv.err)
+	assert.Equal(t, "internal error", s.Message())
+
+	var requestID string
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.RequestInfo); ok {
+			requestID = info.GetRequestId()
+		}
+	}
+	assert.Equal(t, "req-1", requestID)
+
+	srv.err = errors.WithParameter(errors.Invalidf("name is empty"), "name")
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.Error(t, err)
+	assert.Equal(t, "name is empty", errors.UserMessage(err))
+	assert.Equal(t, "name", errors.Parameter(err))
+}
+
+func TestStatus(t *testing.T) {
+	err := errors.WithUserMessage(errors.WithParameter(errors.Conflictf("name taken"), "name"), "name already taken")
+
+	s := Status(err)
+	assert.Equal(t, codes.AlreadyExists, s.Code())
+	assert.Equal(t, "name taken", s.Message())
+
+	require.Len(t, s.Details(), 3)
+	info, ok := s.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, errors.ECONFLICT, info.Reason)
+	assert.Equal(t, Domain, info.Domain)
+
+	br, ok := s.Details()[1].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, br.FieldViolations, 1)
+	assert.Equal(t, "name", br.FieldViolations[0].Field)
+
+	lm, ok := s.Details()[2].(*errdetails.LocalizedMessage)
+	require.True(t, ok)
+	assert.Equal(t, "name already taken", lm.Message)
+
+	assert.Equal(t, codes.OK, Status(nil).Code())
+	assert.Nil(t, FromStatus(Status(nil)))
+
+	// The aggregate keeps its own code and user message, which the aggregated errors are not given
+	sent := errors.Join(
+		errors.WithUserMessage(errors.WithParameter(errors.Invalidf("name is empty"), "name"), "name is required"),
+		errors.WithUserMessage(errors.WithParameter(errors.Conflictf("email taken"), "email"), "email is taken"),
+	)
+	agg := FromStatus(Status(sent))
+	assert.Equal(t, errors.Code(sent), errors.Code(agg))
+	assert.Equal(t, []string{"name", "email"}, errors.Parameters(agg))
+	assert.Equal(t, "name is required; email is taken", errors.UserMessage(agg))
+	require.Len(t, errors.Errors(agg), 2)
+	assert.Equal(t, "name is required", errors.UserMessage(errors.Errors(agg)[0]))
+	assert.NotEqual(t, errors.Code(agg), errors.Code(errors.Errors(agg)[0]))
+
+	// A status from a service not using these details keeps the mapped code
+	plain := FromError(status.Error(codes.NotFound, "no such thing"))
+	assert.Equal(t, errors.ENOTFOUND, errors.Code(plain))
+	assert.Equal(t, "no such thing", status.Convert(plain).Message())
+}
@@ -0,0 +1,149 @@
+// Package grpcerrors carries orlop errors across gRPC calls, encoding the code, parameters, user message and source
+// as google.rpc status details on the server and reconstructing equivalent errors on the client
+package grpcerrors
+
+import (
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is the ErrorInfo domain of the details attached by Status
+const Domain = internal.ErrorInfoDomain
+
+// sourceKey is the ErrorInfo metadata key holding errors.Source
+const sourceKey = "source"
+
+// Status returns the gRPC status for err. The status has the gRPC code mapped from errors.Code and details with:
+//   - an ErrorInfo with the orlop code as the reason and the source in the metadata
+//   - a BadRequest with a field violation for each errors.Parameter, including those of aggregated errors
+//   - a LocalizedMessage with the errors.UserMessage, if one was set, in the first of the locales that has it (see
+//     errors.LocalizedUserMessage)
+//
+// If err is nil, it returns an OK status.
+func Status(err error, locales ...string) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	code := errors.Code(err)
+
+	// Keep the message of an error received from another gRPC service. Convert returns the coded error within err, so
+	// the details are taken from err itself.
+	s := status.New(internal.StandardToGrpc[code], status.Convert(errors.Convert(err)).Message())
+
+	info := &errdetails.ErrorInfo{
+		Reason: code,
+		Domain: Domain,
+	}
+	if source := errors.Source(err); len(source) > 0 {
+		info.Metadata = map[string]string{
+			sourceKey: source,
+		}
+	}
+
+	details := []protoadapt.MessageV1{info}
+
+	br := &errdetails.BadRequest{}
+	for _, e := range errors.Errors(err) {
+		if param := errors.Parameter(e); len(param) > 0 {
+			description, _ := errors.LocalizedUserMessage(e, locales...)
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       param,
+				Description: description,
+			})
+		}
+	}
+	if len(br.FieldViolations) > 0 {
+		details = append(details, br)
+	}
+
+	var um internal.UserMessage
+	if errors.As(err, &um) {
+		msg, locale := errors.LocalizedUserMessage(err, locales...)
+		details = append(details, &errdetails.LocalizedMessage{
+			Locale:  locale,
+			Message: msg,
+		})
+	}
+
+	if ds, detailsErr := s.WithDetails(details...); detailsErr == nil {
+		return ds
+	}
+
+	return s
+}
+
+// FromStatus returns an orlop error equivalent to the one the status was created from by Status, such that the code,
+// parameters, user message and source are preserved. A status without details is given the orlop code mapped from
+// its gRPC code. If the status is OK, it returns nil.
+func FromStatus(s *status.Status) error {
+	if s.Code() == codes.OK {
+		return nil
+	}
+
+	code := internal.GrpcToStandard[s.Code()]
+	var source, userMessage string
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	for _, detail := range s.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == Domain {
+				code = d.GetReason()
+				source = d.GetMetadata()[sourceKey]
+			}
+
+		case *errdetails.BadRequest:
+			violations = append(violations, d.GetFieldViolations()...)
+
+		case *errdetails.LocalizedMessage:
+			userMessage = d.GetMessage()
+		}
+	}
+
+	var err error
+	if len(violations) > 1 {
+		// Each aggregated error had a parameter, so rebuild the aggregate from the violations. The codes of the
+		// aggregated errors are not sent, so only the aggregate has one.
+		var errs []error
+		for _, v := range violations {
+			e := errors.WithParameter(errors.New(v.GetDescription()), v.GetField())
+			errs = append(errs, errors.WithUserMessage(e, v.GetDescription()))
+		}
+		err = errors.WithCode(errors.Join(errs...), code)
+	} else {
+		err = errors.WithCode(s.Err(), code)
+		if len(violations) == 1 {
+			err = errors.WithParameter(err, violations[0].GetField())
+		}
+	}
+
+	if len(userMessage) > 0 {
+		err = errors.WithUserMessage(err, userMessage)
+	}
+
+	if len(source) > 0 {
+		err = errors.WithSource(err, source)
+	}
+
+	return err
+}
+
+// FromError returns the orlop error equivalent to an error returned by a gRPC call, as FromStatus. Errors that are
+// not gRPC status errors are returned as is.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(s)
+}
@@ -0,0 +1,18 @@
+package errors
+
+import "github.com/pkg/errors"
+
+// Internalf returns a new error with EINTERNAL
+func Internalf(format string, args ...any) error {
+	return Internal(errors.Errorf(format, args...))
+}
+
+// Internal returns an error with EINTERNAL
+func Internal(err error) error {
+	return WithCode(err, EINTERNAL)
+}
+
+// IsInternal returns true if the error is an internal error
+func IsInternal(err error) bool {
+	return Code(err) == EINTERNAL
+}
@@ -0,0 +1,6 @@
+package internal
+
+type Fields interface {
+	error
+	Fields() map[string]any
+}
@@ -2,6 +2,10 @@ package internal
 
 import "google.golang.org/grpc/status"
 
+// ErrorInfoDomain is the domain of the google.rpc.ErrorInfo details describing orlop errors, shared by the gRPC
+// status and protobuf encodings so they stay compatible
+const ErrorInfoDomain = "go.ketch.com/lib/orlop"
+
 type GRPCStatus interface {
 	GRPCStatus() *status.Status
 }
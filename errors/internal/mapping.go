@@ -7,20 +7,20 @@ import (
 
 var (
 	HttpToStandard = map[int]string{
-		http.StatusBadRequest:                    EINTERNAL,
-		http.StatusUnauthorized:                  EFORBIDDEN,
+		http.StatusBadRequest:                    EINVALID,
+		http.StatusUnauthorized:                  EUNAUTHENTICATED,
 		http.StatusPaymentRequired:               EFORBIDDEN,
 		http.StatusForbidden:                     EFORBIDDEN,
 		http.StatusNotFound:                      ENOTFOUND,
 		http.StatusMethodNotAllowed:              EINVALID,
 		http.StatusNotAcceptable:                 EINVALID,
-		http.StatusProxyAuthRequired:             EFORBIDDEN,
+		http.StatusProxyAuthRequired:             EUNAUTHENTICATED,
 		http.StatusRequestTimeout:                ETIMEOUT,
 		http.StatusConflict:                      ECONFLICT,
 		http.StatusGone:                          ENOTFOUND,
 		http.StatusLengthRequired:                EINVALID,
-		http.StatusPreconditionFailed:            EINVALID,
-		http.StatusRequestEntityTooLarge:         EINVALID,
+		http.StatusPreconditionFailed:            EPRECONDITIONFAILED,
+		http.StatusRequestEntityTooLarge:         EPAYLOADTOOLARGE,
 		http.StatusRequestURITooLong:             EINVALID,
 		http.StatusUnsupportedMediaType:          EINVALID,
 		http.StatusRequestedRangeNotSatisfiable:  EINVALID,
@@ -29,11 +29,11 @@ var (
 		http.StatusMisdirectedRequest:            ECONFIGURATION,
 		http.StatusUnprocessableEntity:           EINVALID,
 		http.StatusLocked:                        ECONFLICT,
-		http.StatusFailedDependency:              EINVALID,
+		http.StatusFailedDependency:              ECANCELED, // was EINVALID, now the inverse of StandardToHTTP
 		http.StatusTooEarly:                      EINVALID,
 		http.StatusUpgradeRequired:               EFORBIDDEN,
-		http.StatusPreconditionRequired:          EINVALID,
-		http.StatusTooManyRequests:               EUNAVAILABLE,
+		http.StatusPreconditionRequired:          EPRECONDITIONFAILED,
+		http.StatusTooManyRequests:               ERATELIMITED,
 		http.StatusRequestHeaderFieldsTooLarge:   EINVALID,
 		http.StatusUnavailableForLegalReasons:    EUNAVAILABLE,
 		http.StatusInternalServerError:           EINTERNAL,
@@ -46,36 +46,44 @@ var (
 		http.StatusInsufficientStorage:           ECONFIGURATION,
 		http.StatusLoopDetected:                  ECONFIGURATION,
 		http.StatusNotExtended:                   EUNIMPLEMENTED,
-		http.StatusNetworkAuthenticationRequired: EFORBIDDEN,
+		http.StatusNetworkAuthenticationRequired: EUNAUTHENTICATED,
 	}
 	StandardToHTTP = map[string]int{
-		"":             http.StatusOK,
-		ECONFLICT:      http.StatusConflict,
-		ECANCELED:      http.StatusFailedDependency,
-		EINTERNAL:      http.StatusInternalServerError,
-		EUNAVAILABLE:   http.StatusServiceUnavailable,
-		EINVALID:       http.StatusBadRequest,
-		ENOTFOUND:      http.StatusNotFound,
-		ETIMEOUT:       http.StatusGatewayTimeout,
-		EFORBIDDEN:     http.StatusForbidden,
-		ECONFIGURATION: http.StatusInternalServerError,
-		EUNIMPLEMENTED: http.StatusInternalServerError,
-		"default":      http.StatusInternalServerError,
+		"":                  http.StatusOK,
+		ECONFLICT:           http.StatusConflict,
+		ECANCELED:           http.StatusFailedDependency,
+		EINTERNAL:           http.StatusInternalServerError,
+		EUNAVAILABLE:        http.StatusServiceUnavailable,
+		EINVALID:            http.StatusBadRequest,
+		ENOTFOUND:           http.StatusNotFound,
+		ETIMEOUT:            http.StatusGatewayTimeout,
+		EFORBIDDEN:          http.StatusForbidden,
+		ECONFIGURATION:      http.StatusInternalServerError,
+		EUNIMPLEMENTED:      http.StatusInternalServerError,
+		EUNAUTHENTICATED:    http.StatusUnauthorized,
+		ERATELIMITED:        http.StatusTooManyRequests,
+		EPRECONDITIONFAILED: http.StatusPreconditionFailed,
+		EPAYLOADTOOLARGE:    http.StatusRequestEntityTooLarge,
+		"default":           http.StatusInternalServerError,
 	}
 
 	StandardToGrpc = map[string]codes.Code{
-		"":             codes.OK,
-		ECONFLICT:      codes.AlreadyExists,
-		ECANCELED:      codes.Canceled,
-		EINTERNAL:      codes.Internal,
-		EUNAVAILABLE:   codes.Unavailable,
-		EINVALID:       codes.InvalidArgument,
-		ENOTFOUND:      codes.NotFound,
-		ETIMEOUT:       codes.DeadlineExceeded,
-		EFORBIDDEN:     codes.Unauthenticated,
-		ECONFIGURATION: codes.Internal,
-		EUNIMPLEMENTED: codes.Unimplemented,
-		"default":      codes.Unknown,
+		"":                  codes.OK,
+		ECONFLICT:           codes.AlreadyExists,
+		ECANCELED:           codes.Canceled,
+		EINTERNAL:           codes.Internal,
+		EUNAVAILABLE:        codes.Unavailable,
+		EINVALID:            codes.InvalidArgument,
+		ENOTFOUND:           codes.NotFound,
+		ETIMEOUT:            codes.DeadlineExceeded,
+		EFORBIDDEN:          codes.PermissionDenied, // was Unauthenticated, which is now EUNAUTHENTICATED
+		ECONFIGURATION:      codes.Internal,
+		EUNIMPLEMENTED:      codes.Unimplemented,
+		EUNAUTHENTICATED:    codes.Unauthenticated,
+		ERATELIMITED:        codes.ResourceExhausted,
+		EPRECONDITIONFAILED: codes.FailedPrecondition,
+		EPAYLOADTOOLARGE:    codes.InvalidArgument,
+		"default":           codes.Unknown,
 	}
 	GrpcToStandard = map[codes.Code]string{
 		codes.OK:                 "",
@@ -86,27 +94,86 @@ var (
 		codes.NotFound:           ENOTFOUND,
 		codes.AlreadyExists:      ECONFLICT,
 		codes.PermissionDenied:   EFORBIDDEN,
-		codes.ResourceExhausted:  ECONFLICT,
-		codes.FailedPrecondition: EINVALID,
+		codes.ResourceExhausted:  ERATELIMITED,
+		codes.FailedPrecondition: EPRECONDITIONFAILED,
 		codes.Aborted:            ECANCELED,
 		codes.OutOfRange:         EINVALID,
 		codes.Unimplemented:      EUNIMPLEMENTED,
 		codes.Internal:           EINTERNAL,
 		codes.Unavailable:        EUNAVAILABLE,
 		codes.DataLoss:           ECONFLICT,
-		codes.Unauthenticated:    EFORBIDDEN,
+		codes.Unauthenticated:    EUNAUTHENTICATED,
+	}
+
+	// StandardToExit maps to process exit codes following the BSD sysexits.h conventions
+	StandardToExit = map[string]int{
+		"":                  0,
+		ECONFLICT:           65,  // EX_DATAERR
+		ECANCELED:           130, // interrupted
+		EINTERNAL:           70,  // EX_SOFTWARE
+		EUNAVAILABLE:        69,  // EX_UNAVAILABLE
+		EINVALID:            64,  // EX_USAGE
+		ENOTFOUND:           66,  // EX_NOINPUT
+		ETIMEOUT:            75,  // EX_TEMPFAIL
+		EFORBIDDEN:          77,  // EX_NOPERM
+		ECONFIGURATION:      78,  // EX_CONFIG
+		EUNIMPLEMENTED:      70,  // EX_SOFTWARE
+		EUNAUTHENTICATED:    77,  // EX_NOPERM
+		ERATELIMITED:        75,  // EX_TEMPFAIL
+		EPRECONDITIONFAILED: 65,  // EX_DATAERR
+		EPAYLOADTOOLARGE:    65,  // EX_DATAERR
+		"default":           1,
 	}
 
 	StandardToMessage = map[string]string{
-		ECONFLICT:      "conflict",
-		EINTERNAL:      "internal error",
-		EUNAVAILABLE:   "service unavailable",
-		EINVALID:       "invalid request",
-		ENOTFOUND:      "not found",
-		ETIMEOUT:       "timeout",
-		ECANCELED:      "canceled",
-		EFORBIDDEN:     "forbidden",
-		ECONFIGURATION: "configuration",
-		EUNIMPLEMENTED: "unimplemented",
+		ECONFLICT:           "conflict",
+		EINTERNAL:           "internal error",
+		EUNAVAILABLE:        "service unavailable",
+		EINVALID:            "invalid request",
+		ENOTFOUND:           "not found",
+		ETIMEOUT:            "timeout",
+		ECANCELED:           "canceled",
+		EFORBIDDEN:          "forbidden",
+		ECONFIGURATION:      "configuration",
+		EUNIMPLEMENTED:      "unimplemented",
+		EUNAUTHENTICATED:    "unauthenticated",
+		ERATELIMITED:        "rate limited",
+		EPRECONDITIONFAILED: "precondition failed",
+		EPAYLOADTOOLARGE:    "payload too large",
+	}
+
+	// StandardSeverity ranks the codes, such that the code of an aggregate error is that of its most severe child.
+	// Failures of the service outrank failures of the request, as the latter cannot be fixed by the caller until the
+	// former are resolved.
+	StandardSeverity = map[string]int{
+		"":                  0,
+		ENOTFOUND:           1,
+		EINVALID:            2,
+		EPAYLOADTOOLARGE:    2,
+		ECONFLICT:           3,
+		EPRECONDITIONFAILED: 3,
+		EFORBIDDEN:          4,
+		EUNAUTHENTICATED:    4,
+		ECANCELED:           5,
+		EUNIMPLEMENTED:      6,
+		ERATELIMITED:        7,
+		ETIMEOUT:            7,
+		EUNAVAILABLE:        8,
+		ECONFIGURATION:      9,
+		EINTERNAL:           10,
+		"default":           10,
+	}
+
+	// TemporaryCodes are the codes of errors for which retrying the operation may succeed
+	TemporaryCodes = map[string]bool{
+		EINTERNAL:    true,
+		EUNAVAILABLE: true,
+		ETIMEOUT:     true,
+		ERATELIMITED: true,
+	}
+
+	// TimeoutCodes are the codes of errors caused by an operation timing out
+	TimeoutCodes = map[string]bool{
+		ETIMEOUT: true,
 	}
 )
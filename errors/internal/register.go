@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+// CodeDefinition defines an error code and how it maps to HTTP statuses, gRPC codes and process exit codes
+type CodeDefinition struct {
+	Code       string     // the error code (e.g., `rate_limited`)
+	HTTPStatus int        // the HTTP status (default that of EINTERNAL)
+	GRPCCode   codes.Code // the gRPC code (default that of EINTERNAL)
+	ExitCode   int        // the process exit code (default that of EINTERNAL)
+	Message    string     // the default message (default the code)
+	Severity   int        // the rank of the code in an aggregate error, between 0 and 10 (EINTERNAL)
+	Temporary  bool       // whether retrying the operation may succeed
+	Timeout    bool       // whether the error is caused by a timeout
+}
+
+// RegisterCode adds the code to the mappings. The HTTP status and gRPC code map back to the new code unless they
+// already map back to a code that maps to them, so registering a code never breaks an existing round trip.
+func RegisterCode(def CodeDefinition) error {
+	if len(def.Code) == 0 || def.Code == "default" {
+		return fmt.Errorf("invalid error code '%s'", def.Code)
+	}
+	if _, ok := StandardToMessage[def.Code]; ok {
+		return fmt.Errorf("error code '%s' is already registered", def.Code)
+	}
+
+	if def.HTTPStatus == 0 {
+		def.HTTPStatus = StandardToHTTP[EINTERNAL]
+	}
+	if def.GRPCCode == codes.OK {
+		def.GRPCCode = StandardToGrpc[EINTERNAL]
+	}
+	if def.ExitCode == 0 {
+		def.ExitCode = StandardToExit[EINTERNAL]
+	}
+	if len(def.Message) == 0 {
+		def.Message = def.Code
+	}
+
+	StandardToHTTP[def.Code] = def.HTTPStatus
+	StandardToGrpc[def.Code] = def.GRPCCode
+	StandardToExit[def.Code] = def.ExitCode
+	StandardToMessage[def.Code] = def.Message
+	StandardSeverity[def.Code] = def.Severity
+	TemporaryCodes[def.Code] = def.Temporary
+	TimeoutCodes[def.Code] = def.Timeout
+
+	if existing, ok := HttpToStandard[def.HTTPStatus]; !ok || StandardToHTTP[existing] != def.HTTPStatus {
+		HttpToStandard[def.HTTPStatus] = def.Code
+	}
+	if existing, ok := GrpcToStandard[def.GRPCCode]; !ok || StandardToGrpc[existing] != def.GRPCCode {
+		GrpcToStandard[def.GRPCCode] = def.Code
+	}
+
+	return nil
+}
@@ -37,6 +37,11 @@ const (
 	EFORBIDDEN     = "forbidden"     // operation is not authorized
 	ECONFIGURATION = "configuration" // configuration error
 	EUNIMPLEMENTED = "unimplemented" // unimplemented error
+
+	EUNAUTHENTICATED    = "unauthenticated"     // caller is not authenticated
+	ERATELIMITED        = "rate_limited"        // too many requests
+	EPRECONDITIONFAILED = "precondition_failed" // precondition of the operation not met
+	EPAYLOADTOOLARGE    = "payload_too_large"   // request payload too large
 )
 
 func NewStandardError(err error, code string) error {
@@ -82,7 +87,7 @@ func (c StandardError) Timeout() bool {
 		return to.Timeout()
 	}
 
-	return c.code == ETIMEOUT
+	return TimeoutCodes[c.code]
 }
 
 func (c StandardError) Temporary() bool {
@@ -91,5 +96,5 @@ func (c StandardError) Temporary() bool {
 		return temp.Temporary()
 	}
 
-	return c.code == EINTERNAL || c.code == EUNAVAILABLE || c.code == ETIMEOUT
+	return TemporaryCodes[c.code]
 }
@@ -4,3 +4,8 @@ type UserMessage interface {
 	error
 	UserMessage() string
 }
+
+type UserMessageID interface {
+	error
+	UserMessageID() (string, map[string]any)
+}
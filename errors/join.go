@@ -0,0 +1,190 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// multiError is an aggregate of errors, each of which keeps its own code, parameter and user message
+type multiError struct {
+	errs []error
+}
+
+// Join returns an error that aggregates the given errors, discarding any nil errors. Aggregates are flattened, so
+// joining aggregates yields a single aggregate of all their errors.
+//
+// The code of the aggregate is the code of its most severe error (e.g., EINTERNAL outranks EINVALID), and
+// errors.Is and errors.As match any of the errors.
+// If all errors are nil, Join returns nil. If only one error is not nil, Join returns that error.
+func Join(errs ...error) error {
+	var flat []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if m, ok := err.(*multiError); ok {
+			flat = append(flat, m.errs...)
+		} else {
+			flat = append(flat, err)
+		}
+	}
+
+	switch len(flat) {
+	case 0:
+		return nil
+
+	case 1:
+		return flat[0]
+
+	default:
+		return &multiError{errs: flat}
+	}
+}
+
+// Append appends errs to err, returning the aggregate as Join.
+func Append(err error, errs ...error) error {
+	return Join(append([]error{err}, errs...)...)
+}
+
+// Errors returns the errors aggregated in err. If err is not an aggregate, it returns err itself.
+// If err is nil, it returns nil.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	var m *multiError
+	if As(err, &m) {
+		return append([]error(nil), m.errs...)
+	}
+
+	return []error{err}
+}
+
+// Parameters returns the parameters associated with the errors aggregated in err, in order.
+// If err is nil, it returns nil.
+func Parameters(err error) []string {
+	var params []string
+	for _, e := range Errors(err) {
+		if param := Parameter(e); len(param) > 0 {
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+func (m *multiError) Error() string {
+	msgs := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (m *multiError) Unwrap() []error {
+	return m.errs
+}
+
+func (m *multiError) ErrorCode() string {
+	code := Code(m.errs[0])
+	for _, err := range m.errs[1:] {
+		if c := Code(err); severity(c) > severity(code) {
+			code = c
+		}
+	}
+
+	return code
+}
+
+func (m *multiError) StatusCode() int {
+	return internal.StandardToHTTP[m.ErrorCode()]
+}
+
+func (m *multiError) Status() int {
+	return internal.StandardToHTTP[m.ErrorCode()]
+}
+
+func (m *multiError) UserMessage() string {
+	msgs := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		msgs = append(msgs, UserMessage(err))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Temporary returns true if all the errors are temporary, as only then may retrying succeed
+func (m *multiError) Temporary() bool {
+	for _, err := range m.errs {
+		if !IsTemporary(err) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GRPCStatus returns the status with the code of the aggregate, detailing the parameter of each error as a
+// field violation
+func (m *multiError) GRPCStatus() *status.Status {
+	s := status.New(internal.StandardToGrpc[m.ErrorCode()], m.UserMessage())
+
+	br := &errdetails.BadRequest{}
+	for _, err := range m.errs {
+		if param := Parameter(err); len(param) > 0 {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       param,
+				Description: UserMessage(err),
+			})
+		}
+	}
+
+	if len(br.FieldViolations) > 0 {
+		if ds, err := s.WithDetails(br); err == nil {
+			return ds
+		}
+	}
+
+	return s
+}
+
+// jsonError is an error rendered in an HTTP response
+type jsonError struct {
+	Code      string      `json:"code"`
+	Parameter string      `json:"parameter,omitempty"`
+	Message   string      `json:"message"`
+	Errors    []jsonError `json:"errors,omitempty"`
+}
+
+// MarshalJSON renders the aggregate and each of its errors for an HTTP response body
+func (m *multiError) MarshalJSON() ([]byte, error) {
+	v := jsonError{
+		Code:    m.ErrorCode(),
+		Message: m.UserMessage(),
+	}
+
+	for _, err := range m.errs {
+		v.Errors = append(v.Errors, jsonError{
+			Code:      Code(err),
+			Parameter: Parameter(err),
+			Message:   UserMessage(err),
+		})
+	}
+
+	return json.Marshal(v)
+}
+
+func severity(code string) int {
+	if s, ok := internal.StandardSeverity[code]; ok {
+		return s
+	}
+
+	return internal.StandardSeverity["default"]
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJoin(t *testing.T) {
+	name := WithUserMessage(WithParameter(Invalidf("name is empty"), "name"), "name is required")
+	age := WithUserMessage(WithParameter(Invalidf("age is negative"), "age"), "age must be positive")
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, Join())
+		assert.Nil(t, Join(nil, nil))
+		assert.Nil(t, Append(nil))
+		assert.Equal(t, name, Join(nil, name))
+	})
+
+	t.Run("children", func(t *testing.T) {
+		err := Join(name, nil, age)
+
+		assert.Equal(t, "name is empty; age is negative", err.Error())
+		assert.Equal(t, []error{name, age}, Errors(err))
+		assert.Equal(t, []string{"name", "age"}, Parameters(err))
+		assert.Equal(t, "name is required; age must be positive", UserMessage(err))
+		assert.Equal(t, EINVALID, Code(err))
+		assert.Equal(t, http.StatusBadRequest, StatusCode(err))
+	})
+
+	t.Run("flattened", func(t *testing.T) {
+		other := Conflictf("already exists")
+		err := Append(Join(name, age), other)
+
+		assert.Equal(t, []error{name, age, other}, Errors(err))
+		assert.Equal(t, []error{other}, Errors(other))
+		assert.Equal(t, []error{name, age, other}, Errors(Wrap(err, "failed")))
+	})
+
+	t.Run("severity", func(t *testing.T) {
+		assert.Equal(t, ECONFLICT, Code(Join(name, Conflictf("conflict"), age)))
+		assert.Equal(t, EUNAVAILABLE, Code(Join(name, Unavailablef("unavailable"), Conflictf("conflict"))))
+		assert.Equal(t, EINTERNAL, Code(Join(New("unknown"), Unavailablef("unavailable"))))
+		assert.Equal(t, EINTERNAL, Code(Wrap(Join(name, New("unknown")), "failed")))
+		assert.Equal(t, ENOTFOUND, Code(Join(fs.ErrNotExist, NotFoundf("not found"))))
+	})
+
+	t.Run("is and as", func(t *testing.T) {
+		err := Join(name, Wrap(fs.ErrNotExist, "failed"))
+
+		assert.True(t, Is(err, fs.ErrNotExist))
+		assert.True(t, Is(Wrap(err, "failed"), name))
+		assert.False(t, Is(err, fs.ErrExist))
+
+		var pe *fs.PathError
+		assert.True(t, As(Join(name, &fs.PathError{Op: "open", Err: fs.ErrNotExist}), &pe))
+		assert.Equal(t, "open", pe.Op)
+	})
+
+	t.Run("temporary", func(t *testing.T) {
+		var temp interface{ Temporary() bool }
+
+		require.True(t, As(Convert(Join(Unavailablef("unavailable"), Timeoutf("timeout"))), &temp))
+		assert.True(t, temp.Temporary())
+
+		require.True(t, As(Convert(Join(Unavailablef("unavailable"), name)), &temp))
+		assert.False(t, temp.Temporary())
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		s, ok := status.FromError(Join(name, Invalidf("no parameter"), age))
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, s.Code())
+
+		require.Len(t, s.Details(), 1)
+		br, ok := s.Details()[0].(*errdetails.BadRequest)
+		require.True(t, ok)
+		require.Len(t, br.FieldViolations, 2)
+		assert.Equal(t, "name", br.FieldViolations[0].Field)
+		assert.Equal(t, "name is required", br.FieldViolations[0].Description)
+		assert.Equal(t, "age", br.FieldViolations[1].Field)
+		assert.Equal(t, "age must be positive", br.FieldViolations[1].Description)
+
+		assert.Equal(t, EINVALID, Code(s.Err()))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(Join(name, age))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"code": "invalid",
+			"message": "name is required; age must be positive",
+			"errors": [
+				{"code": "invalid", "parameter": "name", "message": "name is required"},
+				{"code": "invalid", "parameter": "age", "message": "age must be positive"}
+			]
+		}`, string(b))
+	})
+}
@@ -0,0 +1,275 @@
+package errors
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrorInfo metadata keys of errors encoded by ToProto
+const (
+	wireParameterKey = "parameter"
+	wireSourceKey    = "source"
+	wireTemporaryKey = "temporary"
+	wireTimeoutKey   = "timeout"
+)
+
+// wireError is the serialized form of an error
+type wireError struct {
+	Code        string      `json:"code"`
+	Message     string      `json:"message"`
+	UserMessage string      `json:"userMessage,omitempty"`
+	Parameter   string      `json:"parameter,omitempty"`
+	Source      string      `json:"source,omitempty"`
+	Temporary   bool        `json:"temporary,omitempty"`
+	Timeout     bool        `json:"timeout,omitempty"`
+	Errors      []wireError `json:"errors,omitempty"`
+}
+
+// Marshal encodes err as JSON for a message queue or storage, with the answers of Code, UserMessage, Parameter,
+// Source, IsTemporary and whether it is a timeout, for err and each error aggregated in it. Unmarshal reconstructs an
+// equivalent error.
+// If err is nil, it returns `null`.
+func Marshal(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(toWire(err))
+}
+
+// Unmarshal stores in target the error encoded by Marshal, for which Error, Code, UserMessage, Parameter, Source,
+// IsTemporary and whether it is a timeout return the same answers as for the original. Unknown fields are ignored.
+// If data is `null`, it stores nil. If data cannot be decoded, it returns an EINVALID error and target is unchanged.
+func Unmarshal(data []byte, target *error) error {
+	var w *wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return Invalid(Wrap(err, "could not unmarshal error"))
+	}
+
+	if w == nil {
+		*target = nil
+	} else {
+		*target = fromWire(*w)
+	}
+
+	return nil
+}
+
+// MarshalProto encodes err as a google.rpc.Status protobuf message, as ToProto.
+// If err is nil, it returns an empty message.
+func MarshalProto(err error) ([]byte, error) {
+	return proto.Marshal(ToProto(err))
+}
+
+// UnmarshalProto stores in target the error encoded by MarshalProto, as FromProto. If data cannot be decoded, it
+// returns an EINVALID error and target is unchanged.
+func UnmarshalProto(data []byte, target *error) error {
+	s := &spb.Status{}
+	if err := proto.Unmarshal(data, s); err != nil {
+		return Invalid(Wrap(err, "could not unmarshal error"))
+	}
+
+	*target = FromProto(s)
+	return nil
+}
+
+// ToProto returns err as a google.rpc.Status, for embedding in other protobuf messages. The status has the gRPC code
+// mapped from the orlop code and details with:
+//   - an ErrorInfo with the orlop code as the reason and the parameter, source, temporary and timeout in the metadata
+//   - a LocalizedMessage with the user message, if it differs from the message
+//   - a Status for each error aggregated in err
+//
+// If err is nil, it returns nil.
+func ToProto(err error) *spb.Status {
+	if err == nil {
+		return nil
+	}
+
+	return toProto(toWire(err))
+}
+
+// FromProto reconstructs the error encoded by ToProto. Details of other types are ignored, and a status without an
+// ErrorInfo is given the code mapped from its gRPC code.
+// If s is nil or OK, it returns nil.
+func FromProto(s *spb.Status) error {
+	if s == nil || s.GetCode() == 0 && len(s.GetDetails()) == 0 {
+		return nil
+	}
+
+	return fromWire(fromProto(s))
+}
+
+func toWire(err error) wireError {
+	w := wireError{
+		Code:        Code(err),
+		Message:     err.Error(),
+		UserMessage: UserMessage(err),
+		Parameter:   Parameter(err),
+		Source:      Source(err),
+		Temporary:   IsTemporary(err),
+		Timeout:     isTimeout(err),
+	}
+
+	if errs := Errors(err); len(errs) > 1 {
+		for _, e := range errs {
+			w.Errors = append(w.Errors, toWire(e))
+		}
+	}
+
+	return w
+}
+
+// fromWire reconstructs an error, adding only the attributes that differ from those the error would otherwise have
+func fromWire(w wireError) error {
+	var err error
+	if len(w.Errors) > 0 {
+		errs := make([]error, 0, len(w.Errors))
+		for _, e := range w.Errors {
+			errs = append(errs, fromWire(e))
+		}
+
+		err = Join(errs...)
+		if err.Error() != w.Message {
+			err = &decodedError{err, w.Message, w.Temporary, w.Timeout}
+		}
+	} else {
+		err = &decodedError{nil, w.Message, w.Temporary, w.Timeout}
+	}
+
+	if Code(err) != w.Code {
+		err = WithCode(err, w.Code)
+	}
+	if Parameter(err) != w.Parameter {
+		err = WithParameter(err, w.Parameter)
+	}
+	if UserMessage(err) != w.UserMessage {
+		err = WithUserMessage(err, w.UserMessage)
+	}
+	if Source(err) != w.Source {
+		err = WithSource(err, w.Source)
+	}
+
+	return err
+}
+
+func toProto(w wireError) *spb.Status {
+	info := &errdetails.ErrorInfo{
+		Reason:   w.Code,
+		Domain:   internal.ErrorInfoDomain,
+		Metadata: make(map[string]string),
+	}
+	if len(w.Parameter) > 0 {
+		info.Metadata[wireParameterKey] = w.Parameter
+	}
+	if len(w.Source) > 0 {
+		info.Metadata[wireSourceKey] = w.Source
+	}
+	if w.Temporary {
+		info.Metadata[wireTemporaryKey] = strconv.FormatBool(w.Temporary)
+	}
+	if w.Timeout {
+		info.Metadata[wireTimeoutKey] = strconv.FormatBool(w.Timeout)
+	}
+
+	s := &spb.Status{
+		Code:    int32(internal.StandardToGrpc[w.Code]),
+		Message: w.Message,
+	}
+
+	details := []proto.Message{info}
+	if w.UserMessage != w.Message {
+		details = append(details, &errdetails.LocalizedMessage{
+			Locale:  DefaultLocale(),
+			Message: w.UserMessage,
+		})
+	}
+	for _, e := range w.Errors {
+		details = append(details, toProto(e))
+	}
+
+	for _, detail := range details {
+		if a, err := anypb.New(detail); err == nil {
+			s.Details = append(s.Details, a)
+		}
+	}
+
+	return s
+}
+
+func fromProto(s *spb.Status) wireError {
+	w := wireError{
+		Code:        internal.GrpcToStandard[codes.Code(s.GetCode())],
+		Message:     s.GetMessage(),
+		UserMessage: s.GetMessage(),
+	}
+
+	for _, a := range s.GetDetails() {
+		detail, err := a.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() != internal.ErrorInfoDomain {
+				continue
+			}
+
+			w.Code = d.GetReason()
+			w.Parameter = d.GetMetadata()[wireParameterKey]
+			w.Source = d.GetMetadata()[wireSourceKey]
+			w.Temporary, _ = strconv.ParseBool(d.GetMetadata()[wireTemporaryKey])
+			w.Timeout, _ = strconv.ParseBool(d.GetMetadata()[wireTimeoutKey])
+
+		case *errdetails.LocalizedMessage:
+			w.UserMessage = d.GetMessage()
+
+		case *spb.Status:
+			w.Errors = append(w.Errors, fromProto(d))
+		}
+	}
+
+	return w
+}
+
+// isTimeout returns true if the error is caused by a timeout
+func isTimeout(err error) bool {
+	var to internal.Timeout
+	return As(Convert(err), &to) && to.Timeout()
+}
+
+// decodedError is an error reconstructed by Unmarshal or FromProto, with the message and classification of the
+// original error. For an aggregate, it wraps the aggregated errors.
+type decodedError struct {
+	error
+	message   string
+	temporary bool
+	timeout   bool
+}
+
+func (e *decodedError) Error() string {
+	return e.message
+}
+
+func (e *decodedError) Cause() error {
+	return e.error
+}
+
+func (e *decodedError) Unwrap() error {
+	return e.error
+}
+
+func (e *decodedError) Temporary() bool {
+	return e.temporary
+}
+
+func (e *decodedError) Timeout() bool {
+	return e.timeout
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestMarshal(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+	}{
+		{"plain", New("boom")},
+		{"coded", NotFoundf("no such user")},
+		{"wrapped", Wrap(WithSource(Invalidf("bad name"), "users"), "create failed")},
+		{"user message", WithUserMessage(WithParameter(Conflictf("taken"), "email"), "The email is taken")},
+		{"temporary", Unavailablef("partner down")},
+		{"timeout", Timeoutf("partner slow")},
+		{"context", Canceled(context.DeadlineExceeded)},
+		{"aggregate", Join(
+			WithParameter(Invalidf("name is required"), "name"),
+			WithSource(WithParameter(Invalidf("email is invalid"), "email"), "validator"),
+		)},
+		{"wrapped aggregate", WithSource(Wrap(Join(Unavailablef("a"), Timeoutf("b")), "sync"), "job")},
+	} {
+		assertEquivalent := func(t *testing.T, decoded error) {
+			assert.Equal(t, tt.err.Error(), decoded.Error())
+			assert.Equal(t, Code(tt.err), Code(decoded))
+			assert.Equal(t, UserMessage(tt.err), UserMessage(decoded))
+			assert.Equal(t, Parameter(tt.err), Parameter(decoded))
+			assert.Equal(t, Parameters(tt.err), Parameters(decoded))
+			assert.Equal(t, Source(tt.err), Source(decoded))
+			assert.Equal(t, IsTemporary(tt.err), IsTemporary(decoded))
+			assert.Equal(t, isTimeout(tt.err), isTimeout(decoded))
+			assert.Equal(t, StatusCode(tt.err), StatusCode(decoded))
+		}
+
+		t.Run(tt.name+"/json", func(t *testing.T) {
+			b, err := Marshal(tt.err)
+			require.NoError(t, err)
+
+			var decoded error
+			require.NoError(t, Unmarshal(b, &decoded))
+			assertEquivalent(t, decoded)
+		})
+
+		t.Run(tt.name+"/proto", func(t *testing.T) {
+			b, err := MarshalProto(tt.err)
+			require.NoError(t, err)
+
+			var decoded error
+			require.NoError(t, UnmarshalProto(b, &decoded))
+			assertEquivalent(t, decoded)
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	b, err := Marshal(nil)
+	require.NoError(t, err)
+	decoded := New("previous")
+	require.NoError(t, Unmarshal(b, &decoded))
+	assert.Nil(t, decoded)
+
+	err = Unmarshal([]byte("{"), &decoded)
+	assert.Equal(t, EINVALID, Code(err))
+	assert.Nil(t, decoded)
+
+	data := []byte(`{"code":"not_found","message":"no such user","parameter":"id","retryAfter":5}`)
+	require.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, ENOTFOUND, Code(decoded))
+	assert.Equal(t, "id", Parameter(decoded))
+}
+
+func TestFromProto(t *testing.T) {
+	assert.Nil(t, FromProto(nil))
+	assert.Nil(t, FromProto(&spb.Status{}))
+
+	// Details of unknown types and domains are ignored
+	retry, err := anypb.New(&errdetails.RetryInfo{})
+	require.NoError(t, err)
+	other, err := anypb.New(&errdetails.ErrorInfo{Reason: "QUOTA", Domain: "example.com"})
+	require.NoError(t, err)
+
+	decoded := FromProto(&spb.Status{
+		Code:    int32(codes.NotFound),
+		Message: "no such user",
+		Details: []*anypb.Any{retry, other},
+	})
+	assert.Equal(t, ENOTFOUND, Code(decoded))
+	assert.Equal(t, "no such user", decoded.Error())
+
+	assert.Equal(t, EINVALID, Code(UnmarshalProto([]byte{0xff}, &decoded)))
+
+	b, err := proto.Marshal(ToProto(Forbiddenf("no access")))
+	require.NoError(t, err)
+	require.NoError(t, UnmarshalProto(b, &decoded))
+	assert.Equal(t, EFORBIDDEN, Code(decoded))
+}
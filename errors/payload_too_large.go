@@ -0,0 +1,18 @@
+package errors
+
+import "github.com/pkg/errors"
+
+// PayloadTooLargef returns a new error with EPAYLOADTOOLARGE
+func PayloadTooLargef(format string, args ...any) error {
+	return PayloadTooLarge(errors.Errorf(format, args...))
+}
+
+// PayloadTooLarge returns an error with EPAYLOADTOOLARGE
+func PayloadTooLarge(err error) error {
+	return WithCode(err, EPAYLOADTOOLARGE)
+}
+
+// IsPayloadTooLarge returns true if the error is a payload too large error
+func IsPayloadTooLarge(err error) bool {
+	return Code(err) == EPAYLOADTOOLARGE
+}
@@ -0,0 +1,18 @@
+package errors
+
+import "github.com/pkg/errors"
+
+// PreconditionFailedf returns a new error with EPRECONDITIONFAILED
+func PreconditionFailedf(format string, args ...any) error {
+	return PreconditionFailed(errors.Errorf(format, args...))
+}
+
+// PreconditionFailed returns an error with EPRECONDITIONFAILED
+func PreconditionFailed(err error) error {
+	return WithCode(err, EPRECONDITIONFAILED)
+}
+
+// IsPreconditionFailed returns true if the error is a precondition failed error
+func IsPreconditionFailed(err error) bool {
+	return Code(err) == EPRECONDITIONFAILED
+}
@@ -0,0 +1,67 @@
+package problem
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/errors/sanitize"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+// maxBodySize limits how much of an error response is read by Parse
+const maxBodySize = 1 << 20
+
+// Writer writes errors to HTTP responses as problem details
+type Writer struct {
+	policy *sanitize.Policy
+}
+
+// NewWriter returns a new Writer, which hides the details of errors as decided by the policy
+func NewWriter(policy *sanitize.Policy) *Writer {
+	return &Writer{
+		policy: policy,
+	}
+}
+
+// Problem returns the Problem for err, sanitized by the policy of the Writer, in the locale of the request in ctx and
+// with the correlation ID of the request
+func (w *Writer) Problem(ctx request.Context, err error) Problem {
+	p := FromError(w.policy.Sanitize(ctx, err), request.Locales(ctx)...)
+	p.RequestID = sanitize.CorrelationID(ctx)
+	return p
+}
+
+// Write writes err to the response as problem details, with the status code mapped from the error code
+func (w *Writer) Write(rw http.ResponseWriter, r *http.Request, err error) {
+	p := w.Problem(r.Context(), err)
+	p.Instance = r.URL.Path
+
+	rw.Header().Set("Content-Type", ContentType)
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.WriteHeader(p.Status)
+	_ = json.NewEncoder(rw).Encode(p)
+}
+
+// Parse returns the orlop error for an HTTP response from an upstream, reading the body of error responses. Problem
+// details are converted as Problem.Err. Other error responses are given the code mapped from the status by
+// errors.WithStatusCode. If the response is not an error, it returns nil.
+func Parse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == ContentType {
+		var p Problem
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxBodySize)).Decode(&p); err == nil {
+			if p.Status == 0 {
+				p.Status = resp.StatusCode
+			}
+			return p.Err()
+		}
+	}
+
+	return errors.WithStatusCode(errors.New(http.StatusText(resp.StatusCode)), resp.StatusCode)
+}
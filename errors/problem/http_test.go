@@ -0,0 +1,128 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/errors/sanitize"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+func serve(t *testing.T, w *Writer, err error) *http.Response {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w.Write(rw, r.WithContext(request.WithID(r.Context(), "req-1")), err)
+	}))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/users")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	return resp
+}
+
+func TestWriter(t *testing.T) {
+	name := errors.WithUserMessage(errors.WithParameter(errors.Invalidf("name is empty"), "name"), "name is required")
+	age := errors.WithUserMessage(errors.WithParameter(errors.Invalidf("age is negative"), "age"), "age must be positive")
+
+	t.Run("render", func(t *testing.T) {
+		resp := serve(t, NewWriter(sanitize.NewPolicy(env.Local())), errors.Join(name, age))
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+
+		var p map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+		assert.Equal(t, map[string]any{
+			"type":   "urn:orlop:error:invalid",
+			"title":  "invalid request",
+			"status": float64(http.StatusBadRequest),
+			"detail": "name is required; age must be positive",
+			"code":   "invalid",
+			"invalid-params": []any{
+				map[string]any{"name": "name", "reason": "name is required"},
+				map[string]any{"name": "age", "reason": "age must be positive"},
+			},
+			"instance":  "/users",
+			"requestId": "req-1",
+		}, p)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, err := range []error{
+			errors.Join(name, age),
+			name,
+			errors.NotFoundf("no such user"),
+			errors.WithUserMessage(errors.Conflictf("duplicate"), "the user already exists"),
+		} {
+			parsed := Parse(serve(t, NewWriter(sanitize.NewPolicy(env.Local())), err))
+			require.Error(t, parsed)
+
+			assert.Equal(t, errors.Code(err), errors.Code(parsed))
+			assert.Equal(t, errors.Parameters(err), errors.Parameters(parsed))
+			assert.Equal(t, errors.UserMessage(err), errors.UserMessage(parsed))
+		}
+	})
+
+	t.Run("internal hidden in production", func(t *testing.T) {
+		err := errors.WithParameter(errors.Internalf("connecting to db-7: password rejected"), "db")
+
+		p := NewWriter(sanitize.NewPolicy(env.Production())).Problem(context.Background(), err)
+		assert.Equal(t, "internal error", p.Detail)
+		assert.Empty(t, p.InvalidParams)
+
+		p = NewWriter(sanitize.NewPolicy(env.Test())).Problem(context.Background(), err)
+		assert.Equal(t, "connecting to db-7: password rejected", p.Detail)
+		assert.Len(t, p.InvalidParams, 1)
+
+		p = NewWriter(sanitize.NewPolicy(env.Production())).Problem(context.Background(), errors.Invalidf("bad input"))
+		assert.Equal(t, "bad input", p.Detail)
+
+		ctx := request.WithID(context.Background(), "req-2")
+		p = NewWriter(sanitize.NewPolicy("staging")).Problem(ctx, errors.Configurationf("missing /etc/app/key.pem"))
+		assert.Equal(t, errors.ECONFIGURATION, p.Code)
+		assert.Equal(t, "configuration", p.Detail)
+		assert.Equal(t, "req-2", p.RequestID)
+	})
+}
+
+func TestParse(t *testing.T) {
+	assert.Nil(t, Parse(&http.Response{StatusCode: http.StatusNoContent}))
+
+	// A malformed problem falls back to the code mapped from the status
+	err := Parse(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": []string{"application/problem+json; charset=utf-8"}},
+		Body:       http.NoBody,
+	})
+	assert.Equal(t, errors.ENOTFOUND, errors.Code(err))
+
+	// A problem from another service keeps the code mapped from its status
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Content-Type": []string{ContentType}},
+		Body: io.NopCloser(strings.NewReader(`{"type":"https://example.com/probs/maintenance","title":"Down for maintenance",` +
+			`"status":503,"detail":"back at noon"}`)),
+	}
+	err = Parse(resp)
+	assert.Equal(t, errors.EUNAVAILABLE, errors.Code(err))
+	assert.Equal(t, "back at noon", errors.UserMessage(err))
+
+	err = Parse(&http.Response{
+		StatusCode: http.StatusGatewayTimeout,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("upstream timed out")),
+	})
+	assert.Equal(t, errors.ETIMEOUT, errors.Code(err))
+}
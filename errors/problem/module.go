@@ -0,0 +1,13 @@
+package problem
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module provides the Writer, which requires the *sanitize.Policy provided by sanitize.Module
+var Module = fx.Module(
+	"problem",
+	fx.Provide(
+		NewWriter,
+	),
+)
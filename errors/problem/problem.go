@@ -0,0 +1,113 @@
+// Package problem renders errors as RFC 7807 problem details (application/problem+json) and parses them back into
+// orlop errors
+package problem
+
+import (
+	"strings"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+// ContentType is the media type of a problem details response
+const ContentType = "application/problem+json"
+
+// TypePrefix is prefixed to the error code to form the type URI of a problem (e.g., `urn:orlop:error:invalid`)
+const TypePrefix = "urn:orlop:error:"
+
+// Problem is an RFC 7807 problem details object, extended with the orlop code, the invalid parameters and the request ID
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+	RequestID     string         `json:"requestId,omitempty"`
+}
+
+// InvalidParam is a parameter that caused the problem, and why
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TypeURI returns the type URI of a problem with the given code
+func TypeURI(code string) string {
+	return TypePrefix + code
+}
+
+// CodeFromType returns the code of a problem with the given type URI, or an empty string if it is not an orlop type
+func CodeFromType(typeURI string) string {
+	if code, ok := strings.CutPrefix(typeURI, TypePrefix); ok {
+		return code
+	}
+
+	return ""
+}
+
+// FromError returns the Problem for err, with the detail and parameter reasons in the first of the locales that has
+// them (see errors.LocalizedUserMessage). The error is rendered as is, so sanitize errors from clients first (see
+// sanitize.Policy), as Writer does.
+func FromError(err error, locales ...string) Problem {
+	code := errors.Code(err)
+	detail, _ := errors.LocalizedUserMessage(err, locales...)
+
+	p := Problem{
+		Type:   TypeURI(code),
+		Title:  internal.StandardToMessage[code],
+		Status: errors.StatusCode(err),
+		Code:   code,
+		Detail: detail,
+	}
+
+	for _, e := range errors.Errors(err) {
+		if param := errors.Parameter(e); len(param) > 0 {
+			reason, _ := errors.LocalizedUserMessage(e, locales...)
+			p.InvalidParams = append(p.InvalidParams, InvalidParam{
+				Name:   param,
+				Reason: reason,
+			})
+		}
+	}
+
+	return p
+}
+
+// Err returns an orlop error equivalent to the one the Problem was created from, preserving the code, user message
+// and parameters. Problems without an orlop type are given the code mapped from the status by errors.WithStatusCode.
+func (p Problem) Err() error {
+	msg := p.Detail
+	if len(msg) == 0 {
+		msg = p.Title
+	}
+
+	var err error
+	if len(p.InvalidParams) > 1 {
+		var errs []error
+		for _, param := range p.InvalidParams {
+			errs = append(errs, errors.WithUserMessage(errors.WithParameter(p.withCode(errors.New(param.Reason)), param.Name),
+				param.Reason))
+		}
+		err = errors.Join(errs...)
+	} else {
+		err = p.withCode(errors.New(msg))
+		if len(p.InvalidParams) == 1 {
+			err = errors.WithParameter(err, p.InvalidParams[0].Name)
+		}
+		if len(p.Detail) > 0 {
+			err = errors.WithUserMessage(err, p.Detail)
+		}
+	}
+
+	return err
+}
+
+func (p Problem) withCode(err error) error {
+	if code := CodeFromType(p.Type); len(code) > 0 {
+		return errors.WithCode(err, code)
+	}
+
+	return errors.WithStatusCode(err, p.Status)
+}
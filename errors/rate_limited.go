@@ -0,0 +1,18 @@
+package errors
+
+import "github.com/pkg/errors"
+
+// RateLimitedf returns a new error with ERATELIMITED
+func RateLimitedf(format string, args ...any) error {
+	return RateLimited(errors.Errorf(format, args...))
+}
+
+// RateLimited returns an error with ERATELIMITED
+func RateLimited(err error) error {
+	return WithCode(err, ERATELIMITED)
+}
+
+// IsRateLimited returns true if the error is a rate limited error
+func IsRateLimited(err error) bool {
+	return Code(err) == ERATELIMITED
+}
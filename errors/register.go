@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+// CodeDefinition defines an error code and how it maps to HTTP statuses, gRPC codes and process exit codes
+type CodeDefinition = internal.CodeDefinition
+
+// Kind is a registered error code, providing the constructors and predicate for errors with that code
+type Kind string
+
+// RegisterCode registers a new error code, returning its Kind, e.g.:
+//
+//	var Teapot = errors.RegisterCode(errors.CodeDefinition{Code: "teapot", HTTPStatus: http.StatusTeapot})
+//
+// RegisterCode must be called during initialization and panics if the code is empty or already registered.
+func RegisterCode(def CodeDefinition) Kind {
+	if err := internal.RegisterCode(def); err != nil {
+		panic(err)
+	}
+
+	return Kind(def.Code)
+}
+
+// Errorf returns a new error with the code
+func (k Kind) Errorf(format string, args ...any) error {
+	return k.Wrap(errors.Errorf(format, args...))
+}
+
+// Wrap returns an error with the code
+func (k Kind) Wrap(err error) error {
+	return WithCode(err, string(k))
+}
+
+// Is returns true if the error has the code
+func (k Kind) Is(err error) bool {
+	return Code(err) == string(k)
+}
+
+// String returns the code
+func (k Kind) String() string {
+	return string(k)
+}
+
+// Codes returns all the registered error codes
+func Codes() []string {
+	codes := make([]string, 0, len(internal.StandardToMessage))
+	for code := range internal.StandardToMessage {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+	return codes
+}
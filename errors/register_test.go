@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegisterCode(t *testing.T) {
+	teapot := RegisterCode(CodeDefinition{
+		Code:       "test_teapot",
+		HTTPStatus: http.StatusTeapot,
+		GRPCCode:   codes.Aborted,
+		ExitCode:   42,
+		Message:    "I'm a teapot",
+		Severity:   3,
+		Temporary:  true,
+	})
+
+	err := teapot.Errorf("short and stout")
+	assert.True(t, teapot.Is(err))
+	assert.False(t, teapot.Is(Conflictf("conflict")))
+	assert.Equal(t, "test_teapot", Code(Wrap(err, "failed")))
+	assert.Equal(t, http.StatusTeapot, StatusCode(err))
+	assert.Equal(t, 42, ExitCode(err))
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.True(t, IsTemporary(err))
+	assert.Contains(t, Codes(), "test_teapot")
+
+	// 418 was mapped back to ECONFIGURATION, which does not map to 418, so the new code claims it
+	assert.Equal(t, "test_teapot", Code(WithStatusCode(nil, http.StatusTeapot)))
+
+	// Aborted maps back to ECANCELED, which does not map to Aborted either
+	assert.Equal(t, "test_teapot", Code(status.Error(codes.Aborted, "aborted")))
+
+	// 404 and NotFound round trip to ENOTFOUND, so they are not claimed
+	RegisterCode(CodeDefinition{Code: "test_gone", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound})
+	assert.Equal(t, ENOTFOUND, Code(WithStatusCode(nil, http.StatusNotFound)))
+	assert.Equal(t, ENOTFOUND, internal.GrpcToStandard[codes.NotFound])
+
+	// Unset mappings default to those of EINTERNAL, without claiming them
+	other := RegisterCode(CodeDefinition{Code: "test_other"})
+	assert.Equal(t, http.StatusInternalServerError, StatusCode(other.Wrap(nil)))
+	assert.Equal(t, codes.Internal, status.Code(other.Wrap(nil)))
+	assert.Equal(t, 70, ExitCode(other.Wrap(nil)))
+	assert.Equal(t, EINTERNAL, Code(status.Error(codes.Internal, "internal")))
+	assert.Equal(t, EINTERNAL, Code(status.Error(codes.Unknown, "unknown")))
+	assert.Equal(t, "test_other", other.Wrap(nil).Error())
+
+	assert.Panics(t, func() { RegisterCode(CodeDefinition{Code: ECONFLICT}) })
+	assert.Panics(t, func() { RegisterCode(CodeDefinition{}) })
+}
+
+func TestMappingsRoundTrip(t *testing.T) {
+	for _, code := range Codes() {
+		t.Run(code, func(t *testing.T) {
+			httpStatus, ok := internal.StandardToHTTP[code]
+			require.True(t, ok, "no HTTP status")
+			grpcCode, ok := internal.StandardToGrpc[code]
+			require.True(t, ok, "no gRPC code")
+			require.Contains(t, internal.StandardToExit, code)
+			require.Contains(t, internal.StandardSeverity, code)
+
+			// A code may share its HTTP status or gRPC code with another, but then it must map back to a code with
+			// the same status, so converting repeatedly is stable
+			fromHTTP := Code(WithStatusCode(nil, httpStatus))
+			assert.Equal(t, httpStatus, internal.StandardToHTTP[fromHTTP], "HTTP %d maps back to %s", httpStatus, fromHTTP)
+
+			fromGrpc := Code(status.Error(grpcCode, code))
+			assert.Equal(t, grpcCode, internal.StandardToGrpc[fromGrpc], "gRPC %s maps back to %s", grpcCode, fromGrpc)
+		})
+	}
+
+	for httpStatus, code := range internal.HttpToStandard {
+		assert.Contains(t, internal.StandardToMessage, code, "HTTP %d maps to unknown code", httpStatus)
+	}
+
+	for grpcCode := codes.OK + 1; grpcCode <= codes.Unauthenticated; grpcCode++ {
+		assert.Contains(t, internal.StandardToMessage, internal.GrpcToStandard[grpcCode], "gRPC %s", grpcCode)
+	}
+
+	for _, code := range []string{
+		ECONFLICT, ECANCELED, EINTERNAL, EUNAVAILABLE, EINVALID, ENOTFOUND, ETIMEOUT, EFORBIDDEN,
+		EUNAUTHENTICATED, ERATELIMITED, EPRECONDITIONFAILED, EPAYLOADTOOLARGE,
+	} {
+		assert.Equal(t, code, Code(WithStatusCode(nil, StatusCode(WithCode(nil, code)))), "HTTP round trip of %s", code)
+	}
+
+	for _, code := range []string{
+		ECONFLICT, ECANCELED, EINTERNAL, EUNAVAILABLE, EINVALID, ENOTFOUND, ETIMEOUT, EFORBIDDEN, EUNIMPLEMENTED,
+		EUNAUTHENTICATED, ERATELIMITED, EPRECONDITIONFAILED,
+	} {
+		assert.Equal(t, code, Code(status.Error(status.Code(WithCode(nil, code)), code)), "gRPC round trip of %s", code)
+	}
+}
+
+func TestChangedMappings(t *testing.T) {
+	// EFORBIDDEN used to map to Unauthenticated, which now maps to EUNAUTHENTICATED
+	assert.Equal(t, codes.PermissionDenied, status.Code(Forbiddenf("denied")))
+	assert.Equal(t, EFORBIDDEN, Code(status.Error(codes.PermissionDenied, "denied")))
+	assert.Equal(t, EUNAUTHENTICATED, Code(status.Error(codes.Unauthenticated, "unauthenticated")))
+
+	// HTTP 424 used to map to EINVALID, although ECANCELED maps to 424
+	assert.Equal(t, ECANCELED, Code(WithStatusCode(nil, http.StatusFailedDependency)))
+	assert.Equal(t, http.StatusFailedDependency, StatusCode(Canceledf("canceled")))
+}
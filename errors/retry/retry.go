@@ -0,0 +1,134 @@
+// Package retry retries operations that fail with temporary errors
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+)
+
+// Default retry settings
+const (
+	DefaultMaxAttempts     = 5
+	DefaultInitialInterval = 100 * time.Millisecond
+	DefaultMaxInterval     = 10 * time.Second
+	DefaultMultiplier      = 2.0
+	DefaultJitter          = 0.5
+)
+
+type options struct {
+	maxAttempts     int
+	maxElapsedTime  time.Duration
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	multiplier      float64
+	jitter          float64
+}
+
+// Option configures Do
+type Option func(o *options)
+
+// WithMaxAttempts sets the number of times the operation is attempted. If zero, the operation is attempted until it
+// succeeds, fails permanently or the max elapsed time is reached.
+func WithMaxAttempts(attempts int) Option {
+	return func(o *options) {
+		o.maxAttempts = attempts
+	}
+}
+
+// WithMaxElapsedTime sets the time after which no further attempt is started. If zero, there is no limit.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(o *options) {
+		o.maxElapsedTime = d
+	}
+}
+
+// WithBackoff sets the delay before the first retry, the factor by which the delay grows on each subsequent retry and
+// the maximum delay between attempts
+func WithBackoff(initial time.Duration, multiplier float64, maxInterval time.Duration) Option {
+	return func(o *options) {
+		o.initialInterval = initial
+		o.multiplier = multiplier
+		o.maxInterval = maxInterval
+	}
+}
+
+// WithJitter sets the fraction (between 0 and 1) by which each delay is randomly varied, so that clients failing at
+// the same time do not retry in lockstep
+func WithJitter(jitter float64) Option {
+	return func(o *options) {
+		o.jitter = min(max(jitter, 0), 1)
+	}
+}
+
+// Do calls fn until it succeeds, returns an error that is not temporary (see errors.IsTemporary) or the attempts or
+// time allowed are exhausted. The delay between attempts grows exponentially. If ctx is done while waiting, no further
+// attempt is made.
+//
+// If fn does not succeed, Do returns the last error, annotated with the number of attempts made.
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	o := options{
+		maxAttempts:     DefaultMaxAttempts,
+		initialInterval: DefaultInitialInterval,
+		maxInterval:     DefaultMaxInterval,
+		multiplier:      DefaultMultiplier,
+		jitter:          DefaultJitter,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	start := time.Now()
+	interval := o.initialInterval
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.IsTemporary(err) {
+			return errors.Wrapf(err, "failed permanently on attempt %d", attempt)
+		}
+
+		if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			return errors.Wrapf(err, "failed after %d attempts", attempt)
+		}
+
+		delay := o.delay(interval)
+		if o.maxElapsedTime > 0 && time.Since(start)+delay > o.maxElapsedTime {
+			return errors.Wrapf(err, "failed after %d attempts in %s", attempt, time.Since(start).Round(time.Millisecond))
+		}
+
+		log.WithContext(ctx).
+			WithError(err).
+			WithField("attempt", attempt).
+			WithField("delay", delay).
+			Warn("retrying")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "canceled after %d attempts", attempt)
+
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*o.multiplier), o.maxInterval)
+	}
+}
+
+// delay returns the interval randomly varied by the jitter
+func (o options) delay(interval time.Duration) time.Duration {
+	if o.jitter == 0 {
+		return interval
+	}
+
+	delta := o.jitter * float64(interval)
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
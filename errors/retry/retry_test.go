@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+func TestDo(t *testing.T) {
+	fast := WithBackoff(time.Millisecond, 2, 4*time.Millisecond)
+
+	t.Run("succeeds after temporary errors", func(t *testing.T) {
+		hook := test.NewGlobal()
+		defer hook.Reset()
+
+		ctx := request.WithID(context.Background(), "req-1")
+
+		var attempts int
+		err := Do(ctx, func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.Unavailablef("try again")
+			}
+			return nil
+		}, fast)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+
+		entries := hook.AllEntries()
+		require.Len(t, entries, 2)
+		for n, entry := range entries {
+			assert.Equal(t, logrus.WarnLevel, entry.Level)
+			assert.Equal(t, n+1, entry.Data["attempt"])
+			assert.Equal(t, "req-1", entry.Data[string(request.IDKey)])
+		}
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		var attempts int
+		err := Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errors.Invalidf("bad input")
+		}, fast)
+
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+		assert.Equal(t, errors.EINVALID, errors.Code(err))
+		assert.Contains(t, err.Error(), "attempt 1")
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		var attempts int
+		err := Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errors.Timeoutf("too slow")
+		}, fast, WithMaxAttempts(4), WithJitter(0))
+
+		require.Error(t, err)
+		assert.Equal(t, 4, attempts)
+		assert.Equal(t, errors.ETIMEOUT, errors.Code(err))
+		assert.Equal(t, "failed after 4 attempts: too slow", err.Error())
+	})
+
+	t.Run("stops after max elapsed time", func(t *testing.T) {
+		var attempts int
+		err := Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errors.Unavailablef("down")
+		}, WithBackoff(20*time.Millisecond, 1, 20*time.Millisecond), WithJitter(0), WithMaxAttempts(0),
+			WithMaxElapsedTime(50*time.Millisecond))
+
+		require.Error(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, errors.EUNAVAILABLE, errors.Code(err))
+	})
+
+	t.Run("honors context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var attempts int
+		err := Do(ctx, func(ctx context.Context) error {
+			attempts++
+			cancel()
+			return errors.Unavailablef("down")
+		}, WithBackoff(time.Hour, 2, time.Hour))
+
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+		assert.Equal(t, errors.EUNAVAILABLE, errors.Code(err))
+		assert.Contains(t, err.Error(), "canceled after 1 attempts")
+	})
+}
+
+func TestDelay(t *testing.T) {
+	o := options{jitter: 0.5}
+	for n := 0; n < 100; n++ {
+		d := o.delay(100 * time.Millisecond)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
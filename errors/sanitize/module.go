@@ -0,0 +1,12 @@
+package sanitize
+
+import (
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module(
+	"sanitize",
+	fx.Provide(
+		NewPolicy,
+	),
+)
@@ -0,0 +1,101 @@
+// Package sanitize decides which details of errors reach clients, so internal details such as SQL, hostnames and file
+// paths are not disclosed by API responses
+package sanitize
+
+import (
+	"maps"
+
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+// DefaultHiddenCodes are the codes of errors whose details are hidden from clients by a new Policy
+var DefaultHiddenCodes = []string{errors.EINTERNAL, errors.ECONFIGURATION}
+
+// Policy decides which details of an error are visible to clients. Errors with hidden codes are replaced by the
+// generic message of their code, while the messages of other errors, which are meant for users, are kept.
+type Policy struct {
+	reveal bool
+	hidden map[string]bool
+}
+
+// NewPolicy returns the policy for the environment, which hides the details of errors with DefaultHiddenCodes, except
+// in the local and test environments, where everything is revealed to ease debugging
+func NewPolicy(e env.Environment) *Policy {
+	p := &Policy{
+		reveal: e.IsLocal() || e.IsTest(),
+		hidden: make(map[string]bool),
+	}
+
+	for _, code := range DefaultHiddenCodes {
+		p.hidden[code] = true
+	}
+
+	return p
+}
+
+// Hide returns a copy of the policy that also hides the details of errors with the given codes (e.g., custom codes
+// registered by errors.RegisterCode)
+func (p *Policy) Hide(codes ...string) *Policy {
+	hidden := maps.Clone(p.hidden)
+	for _, code := range codes {
+		hidden[code] = true
+	}
+
+	return &Policy{
+		reveal: p.reveal,
+		hidden: hidden,
+	}
+}
+
+// Hidden returns true if the details of err are hidden from clients. The details of aggregated errors are hidden
+// individually.
+func (p *Policy) Hidden(err error) bool {
+	return err != nil && !p.reveal && p.hidden[errors.Code(err)]
+}
+
+// Sanitize returns the error to render to clients in place of err. An error with a hidden code is replaced by an error
+// with the same code and only the generic message of the code, which is localized by the message catalogs (see
+// errors.LocalizedUserMessage), and err is logged with the request fields of ctx, so that it can be found from the
+// CorrelationID given to the client. Other errors are returned as is.
+// If err is nil, it returns nil.
+func (p *Policy) Sanitize(ctx request.Context, err error) error {
+	sanitized, hidden := p.sanitize(err)
+	if hidden {
+		log.WithContext(ctx).WithError(err).Error("error details hidden from client")
+	}
+
+	return sanitized
+}
+
+func (p *Policy) sanitize(err error) (error, bool) {
+	if errs := errors.Errors(err); len(errs) > 1 {
+		var anyHidden bool
+		sanitized := make([]error, 0, len(errs))
+		for _, e := range errs {
+			s, hidden := p.sanitize(e)
+			sanitized = append(sanitized, s)
+			anyHidden = anyHidden || hidden
+		}
+
+		if !anyHidden {
+			return err, false
+		}
+
+		return errors.Join(sanitized...), true
+	}
+
+	if p.Hidden(err) {
+		return errors.WithCode(nil, errors.Code(err)), true
+	}
+
+	return err, false
+}
+
+// CorrelationID returns the ID that clients quote to support to find the full error in the logs, which is the ID of
+// the request in ctx
+func CorrelationID(ctx request.Context) string {
+	return request.ID(ctx)
+}
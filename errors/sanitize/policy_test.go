@@ -0,0 +1,51 @@
+package sanitize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+func TestPolicy(t *testing.T) {
+	ctx := request.WithID(context.Background(), "req-1")
+	internal := errors.Internalf("query failed: SELECT * FROM users")
+	invalid := errors.WithParameter(errors.Invalidf("name is required"), "name")
+
+	t.Run("production", func(t *testing.T) {
+		p := NewPolicy(env.Production())
+
+		assert.Nil(t, p.Sanitize(ctx, nil))
+		assert.Equal(t, invalid, p.Sanitize(ctx, invalid))
+
+		err := p.Sanitize(ctx, errors.WithSource(internal, "db"))
+		assert.Equal(t, errors.EINTERNAL, errors.Code(err))
+		assert.Equal(t, "internal error", errors.UserMessage(err))
+		assert.Empty(t, errors.Source(err))
+
+		err = p.Sanitize(ctx, errors.Join(invalid, internal))
+		assert.Equal(t, []string{"name"}, errors.Parameters(err))
+		assert.Equal(t, "name is required; internal error", errors.UserMessage(err))
+	})
+
+	t.Run("local and test", func(t *testing.T) {
+		for _, e := range []env.Environment{env.Local(), env.Test()} {
+			assert.False(t, NewPolicy(e).Hidden(internal))
+			assert.Equal(t, internal, NewPolicy(e).Sanitize(ctx, internal))
+		}
+	})
+
+	t.Run("hide", func(t *testing.T) {
+		p := NewPolicy(env.Production())
+		hiding := p.Hide(errors.ECONFLICT)
+
+		assert.False(t, p.Hidden(errors.Conflictf("row version 7 of users")))
+		assert.True(t, hiding.Hidden(errors.Conflictf("row version 7 of users")))
+		assert.True(t, hiding.Hidden(internal))
+	})
+
+	assert.Equal(t, "req-1", CorrelationID(ctx))
+}
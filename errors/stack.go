@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Frame is a frame of a stack trace
+type Frame struct {
+	Function string // the fully qualified function name (e.g., `go.ketch.com/lib/orlop/v2/cmd.(*Runner).run`)
+	File     string // the absolute path of the file
+	Line     int
+}
+
+// Stack is a stack trace, innermost frame first
+type Stack []Frame
+
+// packagePath is the import path of this package
+var packagePath = reflect.TypeOf(Frame{}).PkgPath()
+
+// stackTracer is implemented by the errors of pkg/errors that record a stack
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// StackTrace returns the deepest stack recorded in err's error chain (i.e., closest to where the error originated),
+// such as by New, Errorf, Wrap or WithStack. The stack starts at the caller of the constructors of this package. For an
+// aggregate error, it returns the stack of the first error.
+// If no stack was recorded, it returns nil.
+func StackTrace(err error) Stack {
+	var st stackTracer
+	for err != nil {
+		if s, ok := err.(stackTracer); ok {
+			st = s
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+
+		case interface{ Unwrap() []error }:
+			if errs := u.Unwrap(); len(errs) > 0 {
+				err = errs[0]
+			} else {
+				err = nil
+			}
+
+		default:
+			err = nil
+		}
+	}
+
+	if st == nil {
+		return nil
+	}
+
+	trace := st.StackTrace()
+	pcs := make([]uintptr, len(trace))
+	for n, f := range trace {
+		pcs[n] = uintptr(f)
+	}
+
+	var stack Stack
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+
+		// Skip the constructors of this package (e.g., New or Invalidf), so the stack starts at their caller
+		if pkg, _ := splitFunction(frame.Function); len(stack) == 0 && pkg == packagePath && more {
+			continue
+		}
+
+		stack = append(stack, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// Compact returns a line per frame in the form `cmd/lifecycle.go:45 (*Runner).run`, omitting the frames of the Go
+// runtime. Files in the main module are relative to the module, and others are qualified by their package path.
+func (s Stack) Compact() []string {
+	var lines []string
+	for _, f := range s {
+		pkg, fn := splitFunction(f.Function)
+		if pkg == "runtime" {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s:%d %s", relativeFile(pkg, f.File), f.Line, fn))
+	}
+
+	return lines
+}
+
+// String returns the compact lines of the stack, as Compact
+func (s Stack) String() string {
+	return strings.Join(s.Compact(), "\n")
+}
+
+// splitFunction splits a fully qualified function name into the package path and the function
+func splitFunction(function string) (string, string) {
+	lastSlash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[lastSlash+1:], "."); dot >= 0 {
+		return function[:lastSlash+1+dot], function[lastSlash+2+dot:]
+	}
+
+	return "", function
+}
+
+var mainModule = sync.OnceValue(func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+})
+
+// relativeFile returns the path of the file within the main module, or qualified by the package path
+func relativeFile(pkg string, file string) string {
+	name := path.Base(file)
+
+	// External test packages are in the directory of the package under test
+	pkg = strings.TrimSuffix(pkg, "_test")
+
+	switch module := mainModule(); {
+	case pkg == "main" || len(pkg) == 0:
+		return name
+
+	case len(module) > 0 && pkg == module:
+		return name
+
+	case len(module) > 0 && strings.HasPrefix(pkg, module+"/"):
+		return path.Join(strings.TrimPrefix(pkg, module+"/"), name)
+
+	default:
+		return path.Join(pkg, name)
+	}
+}
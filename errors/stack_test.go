@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/errors"
+)
+
+func originate() error {
+	return errors.Invalidf("originated")
+}
+
+func TestStackTrace(t *testing.T) {
+	assert.Nil(t, errors.StackTrace(nil))
+	assert.Nil(t, errors.StackTrace(errors.WithCode(io.EOF, errors.EINVALID)))
+
+	// The deepest stack is where the error originated, not where it was wrapped
+	err := errors.Wrap(errors.WithField(errors.Internal(originate()), "k", "v"), "wrapped")
+	stack := errors.StackTrace(err)
+	require.NotEmpty(t, stack)
+	assert.Equal(t, "go.ketch.com/lib/orlop/v2/errors_test.originate", stack[0].Function)
+	assert.True(t, strings.HasSuffix(stack[0].File, "/errors/stack_test.go"))
+
+	lines := stack.Compact()
+	require.Greater(t, len(lines), 1)
+	assert.Regexp(t, `^errors/stack_test.go:\d+ originate$`, lines[0])
+	assert.Regexp(t, `^errors/stack_test.go:\d+ TestStackTrace$`, lines[1])
+	for _, line := range lines {
+		assert.NotContains(t, line, "runtime/")
+	}
+	assert.Equal(t, strings.Join(lines, "\n"), stack.String())
+
+	// An aggregate has the stack of its first error
+	joined := errors.Join(originate(), errors.New("second"))
+	assert.Equal(t, "go.ketch.com/lib/orlop/v2/errors_test.originate", errors.StackTrace(joined)[0].Function)
+}
+
+func TestStack_Compact(t *testing.T) {
+	stack := errors.Stack{
+		{
+			Function: "go.ketch.com/lib/orlop/v2/cmd.(*Runner).run",
+			File:     "/src/orlop/cmd/lifecycle.go",
+			Line:     45,
+		},
+		{
+			Function: "github.com/spf13/cobra.(*Command).execute",
+			File:     "/go/pkg/mod/github.com/spf13/cobra@v1.8.1/command.go",
+			Line:     983,
+		},
+		{
+			Function: "main.main",
+			File:     "/src/app/main.go",
+			Line:     12,
+		},
+		{
+			Function: "runtime.main",
+			File:     "/usr/local/go/src/runtime/proc.go",
+			Line:     271,
+		},
+	}
+
+	assert.Equal(t, []string{
+		"cmd/lifecycle.go:45 (*Runner).run",
+		"github.com/spf13/cobra/command.go:983 (*Command).execute",
+		"main.go:12 main",
+	}, stack.Compact())
+}
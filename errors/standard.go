@@ -34,4 +34,9 @@ const (
 	EFORBIDDEN     = internal.EFORBIDDEN     // operation is not authorized
 	ECONFIGURATION = internal.ECONFIGURATION // configuration error
 	EUNIMPLEMENTED = internal.EUNIMPLEMENTED // unimplemented error
+
+	EUNAUTHENTICATED    = internal.EUNAUTHENTICATED    // caller is not authenticated
+	ERATELIMITED        = internal.ERATELIMITED        // too many requests
+	EPRECONDITIONFAILED = internal.EPRECONDITIONFAILED // precondition of the operation not met
+	EPAYLOADTOOLARGE    = internal.EPAYLOADTOOLARGE    // request payload too large
 )
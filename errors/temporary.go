@@ -0,0 +1,17 @@
+package errors
+
+import (
+	"go.ketch.com/lib/orlop/v2/errors/internal"
+)
+
+// IsTemporary returns true if the error is temporary, such that retrying the operation may succeed (e.g., EUNAVAILABLE
+// or ETIMEOUT). Errors without a code are converted first.
+// If err is nil, it returns false.
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var temp internal.Temporary
+	return As(Convert(err), &temp) && temp.Temporary()
+}
@@ -0,0 +1,18 @@
+package errors
+
+import "github.com/pkg/errors"
+
+// Unauthenticatedf returns a new error with EUNAUTHENTICATED
+func Unauthenticatedf(format string, args ...any) error {
+	return Unauthenticated(errors.Errorf(format, args...))
+}
+
+// Unauthenticated returns an error with EUNAUTHENTICATED
+func Unauthenticated(err error) error {
+	return WithCode(err, EUNAUTHENTICATED)
+}
+
+// IsUnauthenticated returns true if the error is an unauthenticated error
+func IsUnauthenticated(err error) bool {
+	return Code(err) == EUNAUTHENTICATED
+}
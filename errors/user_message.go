@@ -80,3 +80,37 @@ func UserMessage(err error) string {
 
 	return err.Error()
 }
+
+type messageID struct {
+	error
+	id   string
+	args map[string]any
+}
+
+func (m messageID) Cause() error {
+	return m.error
+}
+
+func (m messageID) Unwrap() error {
+	return m.error
+}
+
+func (m messageID) UserMessageID() (string, map[string]any) {
+	return m.id, m.args
+}
+
+func (m messageID) UserMessage() string {
+	msg, _ := LocalizedUserMessage(m)
+	return msg
+}
+
+// WithUserMessageID adds a user message to err's error chain that is looked up by id in the message catalogs (see
+// RegisterMessages) and rendered with args when localized (see LocalizedUserMessage). UserMessage returns the message
+// in the default locale.
+// Unlike pkg/errors, WithUserMessageID will wrap nil error.
+func WithUserMessageID(err error, id string, args map[string]any) error {
+	if err == nil {
+		err = New("UserMessageID<" + id + ">")
+	}
+	return messageID{err, id, args}
+}
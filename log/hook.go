@@ -0,0 +1,77 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.ketch.com/lib/orlop/v2/errors"
+)
+
+// Keys of the fields added by ErrorHook
+const (
+	ErrorCodeKey      = "errorCode"
+	ErrorSourceKey    = "errorSource"
+	ErrorParameterKey = "errorParameter"
+	ErrorStackKey     = "errorStack"
+)
+
+// ErrorHook expands the error of an entry logged using WithError into the entry, adding the fields of the error (see
+// errors.WithFields) and its code, source and parameter. Fields already set on the entry are not overwritten. It is
+// added to the standard logger by logging.SetupLogging and logging.Module (see SetErrorHook).
+type ErrorHook struct {
+	// StackTraces adds the stack trace of EINTERNAL and ECONFIGURATION errors, which are unexpected and so need to be
+	// located. The stacks of other errors are never added.
+	StackTraces bool
+}
+
+// SetErrorHook adds the hook to the logger, replacing any ErrorHook previously added
+func SetErrorHook(logger *logrus.Logger, hook ErrorHook) {
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		for _, h := range levelHooks {
+			if _, ok := h.(ErrorHook); !ok {
+				hooks[level] = append(hooks[level], h)
+			}
+		}
+	}
+	hooks.Add(hook)
+
+	logger.ReplaceHooks(hooks)
+}
+
+// Levels returns all levels
+func (ErrorHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the fields of the error to the entry
+func (h ErrorHook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok || err == nil {
+		return nil
+	}
+
+	set := func(key string, value any) {
+		if _, exists := entry.Data[key]; !exists {
+			entry.Data[key] = value
+		}
+	}
+
+	for k, v := range errors.Fields(err) {
+		set(k, v)
+	}
+
+	code := errors.Code(err)
+	set(ErrorCodeKey, code)
+	if source := errors.Source(err); len(source) > 0 {
+		set(ErrorSourceKey, source)
+	}
+	if param := errors.Parameter(err); len(param) > 0 {
+		set(ErrorParameterKey, param)
+	}
+	if h.StackTraces && (code == errors.EINTERNAL || code == errors.ECONFIGURATION) {
+		if stack := errors.StackTrace(err); len(stack) > 0 {
+			set(ErrorStackKey, stack.Compact())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package log_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/log"
+)
+
+func TestErrorHook(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(log.ErrorHook{})
+	hook := test.NewLocal(logger)
+
+	err := errors.WithSource(errors.WithParameter(errors.WithField(errors.Invalidf("bad id"), "id", 42), "id"), "api")
+
+	logger.WithField("id", "kept").WithError(err).Error("failed")
+	logger.WithError(errors.New("plain")).Warn("failed")
+	logger.Info("no error")
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, logrus.Fields{
+		logrus.ErrorKey:       err,
+		"id":                  "kept",
+		log.ErrorCodeKey:      errors.EINVALID,
+		log.ErrorSourceKey:    "api",
+		log.ErrorParameterKey: "id",
+	}, entries[0].Data)
+
+	assert.Equal(t, errors.EINTERNAL, entries[1].Data[log.ErrorCodeKey])
+	assert.NotContains(t, entries[1].Data, log.ErrorSourceKey)
+
+	assert.Empty(t, entries[2].Data)
+}
+
+func TestErrorHook_StackTraces(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	log.SetErrorHook(logger, log.ErrorHook{StackTraces: true})
+	logger.WithError(errors.Internalf("unexpected")).Error("failed")
+	logger.WithError(errors.Configurationf("missing key")).Error("failed")
+	logger.WithError(errors.Invalidf("bad input")).Error("failed")
+
+	// Setting the hook again replaces it
+	log.SetErrorHook(logger, log.ErrorHook{StackTraces: false})
+	logger.WithError(errors.Internalf("unexpected")).Error("failed")
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 4)
+
+	stack, ok := entries[0].Data[log.ErrorStackKey].([]string)
+	require.True(t, ok)
+	require.NotEmpty(t, stack)
+	assert.Regexp(t, `^log/hook_test.go:\d+ TestErrorHook_StackTraces$`, stack[0])
+
+	assert.Contains(t, entries[1].Data, log.ErrorStackKey)
+	assert.NotContains(t, entries[2].Data, log.ErrorStackKey)
+	assert.NotContains(t, entries[3].Data, log.ErrorStackKey)
+	assert.Equal(t, errors.EINTERNAL, entries[3].Data[log.ErrorCodeKey])
+
+	// The test hook is kept alongside the error hook, which is not added twice
+	assert.Len(t, logger.Hooks[logrus.ErrorLevel], 2)
+}
+
+func TestErrorHook_NotAddedByImport(t *testing.T) {
+	for _, h := range logrus.StandardLogger().Hooks[logrus.ErrorLevel] {
+		_, ok := h.(log.ErrorHook)
+		assert.False(t, ok)
+	}
+}
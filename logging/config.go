@@ -0,0 +1,17 @@
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.ketch.com/lib/orlop/v2/log"
+)
+
+// Config configures logging
+type Config struct {
+	// StackTraces adds the stack trace of EINTERNAL and ECONFIGURATION errors logged using WithError
+	StackTraces bool `config:"stack_traces,default=true"`
+}
+
+// Configure applies the Config to the standard logger
+func Configure(cfg Config) {
+	log.SetErrorHook(logrus.StandardLogger(), log.ErrorHook{StackTraces: cfg.StackTraces})
+}
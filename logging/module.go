@@ -21,12 +21,15 @@
 package logging
 
 import (
+	"go.ketch.com/lib/orlop/v2/config"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module(
 	"logging",
+	config.Option[Config]("logging"),
 	fx.Provide(
 		New,
 	),
+	fx.Invoke(Configure),
 )
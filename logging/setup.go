@@ -3,6 +3,7 @@ package logging
 import (
 	"github.com/sirupsen/logrus"
 	"go.ketch.com/lib/orlop/v2/env"
+	"go.ketch.com/lib/orlop/v2/log"
 	stdlog "log"
 )
 
@@ -44,5 +45,8 @@ func SetupLogging(env env.Environment, loglevel Level) {
 		})
 	}
 
+	// Expand logged errors until the logging config is applied (see Configure)
+	log.SetErrorHook(logrus.StandardLogger(), log.ErrorHook{StackTraces: true})
+
 	stdlog.SetOutput(logrus.New().Writer())
 }
@@ -39,6 +39,7 @@ var (
 	URLKey         Key = "requestUrl"
 	UserKey        Key = "userId"
 	IntegrationKey Key = "integration"
+	LocaleKey      Key = "locale"
 )
 
 // AllKeys is a slice of all Keys
@@ -52,6 +53,7 @@ var AllKeys = []Key{
 	URLKey,
 	UserKey,
 	IntegrationKey,
+	LocaleKey,
 }
 
 // LowCardinalityKeys is a map of high-cardinality keys
@@ -71,6 +73,7 @@ var KeyMap = map[Key]string{
 	URLKey:         "Request-Url",
 	UserKey:        "User-Id",
 	IntegrationKey: "Integration",
+	LocaleKey:      "Accept-Language",
 }
 
 var MetricsKeyMap = map[Key]string{
@@ -97,6 +100,7 @@ var Setters = map[Key]Setter{
 	URLKey:         WithURL,
 	UserKey:        WithUser,
 	IntegrationKey: WithIntegration,
+	LocaleKey:      WithLocale,
 	TimestampKey: func(ctx context.Context, v string) context.Context {
 		if t, err := time.Parse(time.RFC3339, v); err == nil {
 			return WithTimestamp(ctx, t)
@@ -115,6 +119,7 @@ var Getters = map[Key]Getter{
 	URLKey:         URL,
 	UserKey:        User,
 	IntegrationKey: Integration,
+	LocaleKey:      Locale,
 	TimestampKey: func(ctx Context) string {
 		if ts := Timestamp(ctx); !ts.IsZero() {
 			return ts.Format(time.RFC3339)
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithLocale returns a new context with the given request locale, which may list several locales in order of
+// preference as an Accept-Language header does (e.g., `fr-CA,fr;q=0.9,en;q=0.8`)
+func WithLocale(parent context.Context, locale string) context.Context {
+	if len(locale) == 0 {
+		return parent
+	}
+
+	return WithValue(parent, LocaleKey, locale)
+}
+
+// WithLocaleOption returns a function that sets the given locale on a context
+func WithLocaleOption(locale string) func(ctx context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		if len(Locale(ctx)) == 0 {
+			ctx = WithLocale(ctx, locale)
+		}
+		return ctx
+	}
+}
+
+// Locale returns the request locale or an empty string
+func Locale(ctx Context) string {
+	return Value[string](ctx, LocaleKey)
+}
+
+// Locales returns the locales of the request in order of preference, omitting the wildcard and those with a zero
+// quality value
+func Locales(ctx Context) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	var prefs []weighted
+	for _, part := range strings.Split(Locale(ctx), ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if len(locale) == 0 || locale == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+
+		if quality > 0 {
+			prefs = append(prefs, weighted{locale: locale, quality: quality})
+		}
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].quality > prefs[j].quality
+	})
+
+	locales := make([]string, 0, len(prefs))
+	for _, p := range prefs {
+		locales = append(locales, p.locale)
+	}
+
+	return locales
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.uber.org/fx"
+)
+
+// Job is a function run periodically by the Scheduler, either on a cron Schedule or at a fixed Interval
+type Job struct {
+	// Name identifies the job and is used as the request operation
+	Name string
+
+	// Schedule is a cron expression (e.g., `*/5 * * * *` or `@hourly`)
+	Schedule string
+
+	// Interval is the time between the end of one run and the start of the next. It must be a whole number of seconds.
+	Interval time.Duration
+
+	// Jitter is the maximum random delay added before each run
+	Jitter time.Duration
+
+	// Run is called with a context carrying a fresh request ID and the job name as the operation
+	Run func(ctx context.Context) error
+}
+
+// Option provides a Job from the given constructor. The jobs are only scheduled when the app includes Module.
+func Option(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"jobs"`),
+		),
+	)
+}
+
+// schedule returns the cron.Schedule for the Job
+func (j Job) schedule() (cron.Schedule, error) {
+	if len(j.Name) == 0 {
+		return nil, errors.Configurationf("job name is required")
+	}
+
+	if j.Run == nil {
+		return nil, errors.Configurationf("job '%s' has no Run function", j.Name)
+	}
+
+	switch {
+	case len(j.Schedule) > 0 && j.Interval > 0:
+		return nil, errors.Configurationf("job '%s' must have a schedule or an interval, not both", j.Name)
+
+	case len(j.Schedule) > 0:
+		s, err := cron.ParseStandard(j.Schedule)
+		if err != nil {
+			return nil, errors.Wrapf(errors.Configuration(err), "job '%s' has an invalid schedule", j.Name)
+		}
+		return s, nil
+
+	case j.Interval > 0:
+		// cron would otherwise round the interval down to whole seconds, with a minimum of one
+		if j.Interval%time.Second != 0 {
+			return nil, errors.Configurationf("job '%s' has an interval of %v, which is not a whole number of seconds",
+				j.Name, j.Interval)
+		}
+		return cron.ConstantDelaySchedule{Delay: j.Interval}, nil
+
+	default:
+		return nil, errors.Configurationf("job '%s' must have a schedule or an interval", j.Name)
+	}
+}
@@ -0,0 +1,12 @@
+package scheduler
+
+import (
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module(
+	"scheduler",
+	fx.Provide(
+		New,
+	),
+)
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.ketch.com/lib/orlop/v2/request"
+	"go.uber.org/fx"
+)
+
+// ErrJobRunning is the cause of the ECONFLICT error returned when running a job that is already running
+var ErrJobRunning = errors.New("job is already running")
+
+// Scheduler runs the registered Jobs
+type Scheduler interface {
+	// Jobs returns the names of the registered jobs
+	Jobs() []string
+
+	// Run runs the named job once, returning an error caused by ErrJobRunning if it is already running
+	Run(ctx context.Context, name string) error
+
+	// Start starts running the jobs on their schedules
+	Start(ctx context.Context) error
+
+	// Stop stops scheduling the jobs and waits for any running jobs to complete
+	Stop(ctx context.Context) error
+}
+
+// Params are the parameters for creating a Scheduler
+type Params struct {
+	fx.In
+
+	Logger logging.Logger
+	Jobs   []Job `group:"jobs"`
+}
+
+// New returns a new Scheduler for the given Jobs
+func New(params Params) (Scheduler, error) {
+	s := &schedulerImpl{
+		logger: params.Logger,
+		jobs:   make(map[string]*scheduledJob),
+	}
+
+	for _, job := range params.Jobs {
+		schedule, err := job.schedule()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := s.jobs[job.Name]; ok {
+			return nil, errors.Configurationf("job '%s' is registered more than once", job.Name)
+		}
+
+		s.jobs[job.Name] = &scheduledJob{
+			Job:      job,
+			schedule: schedule,
+		}
+	}
+
+	return s, nil
+}
+
+type scheduledJob struct {
+	Job
+	schedule cron.Schedule
+	running  sync.Mutex
+}
+
+type schedulerImpl struct {
+	logger logging.Logger
+	jobs   map[string]*scheduledJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (s *schedulerImpl) Jobs() []string {
+	var names []string
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (s *schedulerImpl) Run(ctx context.Context, name string) error {
+	job, ok := s.jobs[name]
+	if !ok {
+		return errors.NotFoundf("job '%s' not found", name)
+	}
+
+	return s.run(ctx, job)
+}
+
+func (s *schedulerImpl) Start(_ context.Context) error {
+	// The start context expires once the app has started, so the jobs get their own
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, name := range s.Jobs() {
+		job := s.jobs[name]
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+
+	return nil
+}
+
+func (s *schedulerImpl) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		return errors.Timeoutf("jobs did not complete before the stop timeout")
+	}
+}
+
+// loop runs the job on its schedule until the context is canceled
+func (s *schedulerImpl) loop(ctx context.Context, job *scheduledJob) {
+	for {
+		now := time.Now()
+		next := job.schedule.Next(now)
+		if job.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(job.Jitter))))
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+
+		case <-timer.C:
+			if err := s.run(ctx, job); errors.Is(err, ErrJobRunning) {
+				s.logger.WithField("job", job.Name).Warn("skipping job, previous run has not completed")
+			}
+		}
+	}
+}
+
+// run runs the job once with a fresh request context, logging the outcome
+func (s *schedulerImpl) run(ctx context.Context, job *scheduledJob) (err error) {
+	if !job.running.TryLock() {
+		return errors.Conflict(errors.Wrapf(ErrJobRunning, "could not run job '%s'", job.Name))
+	}
+	defer job.running.Unlock()
+
+	// Start from a blank context so every run has its own request ID, but stop the run if the parent is canceled
+	runCtx, cancel := context.WithCancel(request.Setup(context.Background(), request.WithOperationOption(job.Name)))
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	ctx = runCtx
+
+	logger := s.logger.WithContext(ctx).WithField("job", job.Name)
+	logger.Debug("running job")
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Internalf("job panicked: %v", r)
+		}
+
+		l := logger.WithField("runtime", time.Since(start))
+		if err != nil {
+			l.WithError(err).Error("job failed")
+		} else {
+			l.Info("job completed")
+		}
+	}()
+
+	return job.Run(ctx)
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.ketch.com/lib/orlop/v2/errors"
+	"go.ketch.com/lib/orlop/v2/logging"
+	"go.ketch.com/lib/orlop/v2/request"
+)
+
+func newScheduler(t *testing.T, jobs ...Job) Scheduler {
+	logger, err := logging.New("test", logging.FatalLevel)
+	require.NoError(t, err)
+
+	s, err := New(Params{
+		Logger: logger,
+		Jobs:   jobs,
+	})
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestNew(t *testing.T) {
+	logger, err := logging.New("test", logging.FatalLevel)
+	require.NoError(t, err)
+
+	run := func(ctx context.Context) error { return nil }
+
+	for name, jobs := range map[string][]Job{
+		"no name":          {{Interval: time.Second, Run: run}},
+		"no run":           {{Name: "job", Interval: time.Second}},
+		"no schedule":      {{Name: "job", Run: run}},
+		"both":             {{Name: "job", Schedule: "@hourly", Interval: time.Second, Run: run}},
+		"invalid schedule": {{Name: "job", Schedule: "every tuesday", Run: run}},
+		"sub-second":       {{Name: "job", Interval: 500 * time.Millisecond, Run: run}},
+		"partial second":   {{Name: "job", Interval: 1500 * time.Millisecond, Run: run}},
+		"duplicate":        {{Name: "job", Interval: time.Second, Run: run}, {Name: "job", Schedule: "@hourly", Run: run}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(Params{Logger: logger, Jobs: jobs})
+			require.Error(t, err)
+			assert.True(t, errors.IsConfiguration(err))
+		})
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	var ids []string
+	release := make(chan struct{})
+
+	s := newScheduler(t,
+		Job{
+			Name:     "record",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				assert.Equal(t, "record", request.Operation(ctx))
+				ids = append(ids, request.ID(ctx))
+				return nil
+			},
+		},
+		Job{
+			Name:     "panic",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				panic("boom")
+			},
+		},
+		Job{
+			Name:     "block",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+		},
+	)
+
+	assert.Equal(t, []string{"block", "panic", "record"}, s.Jobs())
+
+	ctx := request.Setup(context.Background(), request.WithOperationOption("parent"))
+	require.NoError(t, s.Run(ctx, "record"))
+	require.NoError(t, s.Run(ctx, "record"))
+	require.Len(t, ids, 2)
+	assert.NotEqual(t, ids[0], ids[1])
+	assert.NotEqual(t, request.ID(ctx), ids[0])
+
+	err := s.Run(ctx, "panic")
+	require.Error(t, err)
+	assert.True(t, errors.IsInternal(err))
+	assert.Contains(t, err.Error(), "boom")
+
+	assert.True(t, errors.IsNotFound(s.Run(ctx, "missing")))
+
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx, "block")
+	}()
+
+	require.Eventually(t, func() bool {
+		err := s.Run(ctx, "block")
+		return errors.IsConflict(err) && errors.Is(err, ErrJobRunning)
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	var runs atomic.Int32
+
+	s := newScheduler(t, Job{
+		Name:     "tick",
+		Interval: time.Second,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	require.NoError(t, s.Start(context.Background()))
+	require.Eventually(t, func() bool { return runs.Load() > 0 }, 3*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Stop(context.Background()))
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// Build metadata set at link time, which takes precedence over the build info embedded by the Go toolchain, e.g.:
+//
+//	go build -ldflags "-X go.ketch.com/lib/orlop/v2/service.version=1.2.3 -X go.ketch.com/lib/orlop/v2/service.buildTime=2024-01-02T15:04:05Z"
+var (
+	version   string
+	revision  string
+	buildTime string
+)
+
+// Dependency is a module the binary was built with
+type Dependency struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Sum     string `json:"sum,omitempty"`
+}
+
+// BuildInfo describes how the binary was built
+type BuildInfo struct {
+	Version      Version      `json:"version"`
+	Path         string       `json:"path,omitempty"`
+	Revision     string       `json:"revision,omitempty"`
+	Dirty        bool         `json:"dirty"`
+	BuildTime    time.Time    `json:"buildTime"`
+	GoVersion    string       `json:"goVersion"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+// ReadBuildInfo returns the BuildInfo of the running binary
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   "devel",
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.Path = bi.Main.Path
+		if len(bi.Main.Version) > 0 && bi.Main.Version != "(devel)" {
+			info.Version = Version(bi.Main.Version)
+		}
+
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+
+			case "vcs.modified":
+				info.Dirty, _ = strconv.ParseBool(s.Value)
+
+			case "vcs.time":
+				info.BuildTime, _ = time.Parse(time.RFC3339, s.Value)
+			}
+		}
+
+		for _, dep := range bi.Deps {
+			d := Dependency{
+				Path:    dep.Path,
+				Version: dep.Version,
+				Sum:     dep.Sum,
+			}
+
+			if dep.Replace != nil {
+				d.Version = dep.Replace.Version
+				d.Sum = dep.Replace.Sum
+			}
+
+			info.Dependencies = append(info.Dependencies, d)
+		}
+	}
+
+	if len(version) > 0 {
+		info.Version = Version(version)
+	}
+	if len(revision) > 0 {
+		info.Revision = revision
+	}
+	if t, err := time.Parse(time.RFC3339, buildTime); err == nil {
+		info.BuildTime = t
+	}
+
+	return info
+}
+
+// Fields returns the build metadata as alternating keys and values suitable for logging
+func (b BuildInfo) Fields() []any {
+	fields := []any{
+		"version", b.Version,
+		"goVersion", b.GoVersion,
+	}
+
+	if len(b.Revision) > 0 {
+		fields = append(fields, "revision", b.Revision, "dirty", b.Dirty)
+	}
+
+	if !b.BuildTime.IsZero() {
+		fields = append(fields, "buildTime", b.BuildTime.Format(time.RFC3339))
+	}
+
+	return fields
+}